@@ -0,0 +1,104 @@
+//go:build unix
+
+package cmd类
+
+import (
+	"os/exec"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAsyncHandle(t *testing.T) {
+	h, err := I设置命令("sh", "-c", "sleep 0.1; exit 3").I运行_异步句柄()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var order []int
+	called := make(chan struct{})
+	h.I添加退出回调(func(c *Cmd, err error) {
+		if c != h.I取命令() {
+			t.Errorf("callback got a different command")
+		}
+		if h.Result() != err {
+			t.Errorf("callback error %v differs from Result %v", err, h.Result())
+		}
+		order = append(order, 1)
+	})
+	h.I添加退出回调(func(*Cmd, error) {
+		order = append(order, 2)
+		close(called)
+	})
+
+	select {
+	case <-h.Done():
+		t.Fatal("Done closed while command is running")
+	case <-time.After(20 * time.Millisecond):
+	}
+	select {
+	case <-h.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Done not closed after command exit")
+	}
+	err = h.Result()
+	if ee, ok := err.(*exec.ExitError); !ok || ee.ExitCode() != 3 {
+		t.Errorf("Result = %v; want exit status 3", err)
+	}
+	if h.Result() != err {
+		t.Errorf("second Result differs")
+	}
+	<-called
+	if len(order) != 2 || order[0] != 1 || order[1] != 2 {
+		t.Errorf("callback order = %v; want [1 2]", order)
+	}
+
+	late := false
+	h.I添加退出回调(func(*Cmd, error) { late = true })
+	if !late {
+		t.Errorf("callback registered after exit was not called immediately")
+	}
+
+	if _, err := I设置命令("/nonexistent/program").I运行_异步句柄(); err == nil {
+		t.Errorf("I运行_异步句柄 of missing program succeeded")
+	}
+}
+
+func TestAsyncHandleCallbackDuringCallbacks(t *testing.T) {
+	h, err := I设置命令("sleep", "0.1").I运行_异步句柄()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	var order []int
+	record := func(i int) {
+		mu.Lock()
+		order = append(order, i)
+		mu.Unlock()
+	}
+	registered := make(chan struct{})
+	all := make(chan struct{})
+	h.I添加退出回调(func(*Cmd, error) {
+		// 在第一个回调执行期间从另一个协程注册，新回调不能与它同时执行。
+		go func() {
+			h.I添加退出回调(func(*Cmd, error) {
+				record(3)
+				close(all)
+			})
+			close(registered)
+		}()
+		<-registered
+		time.Sleep(20 * time.Millisecond)
+		record(1)
+	})
+	h.I添加退出回调(func(*Cmd, error) { record(2) })
+	select {
+	case <-all:
+	case <-time.After(5 * time.Second):
+		t.Fatal("callbacks not called")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(order) != 3 || order[0] != 1 || order[1] != 2 || order[2] != 3 {
+		t.Errorf("callback order = %v; want [1 2 3]", order)
+	}
+}
@@ -0,0 +1,105 @@
+package cmd类
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWindowsCommandLine(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"prog"}, `prog`},
+		{[]string{`C:\Program Files\x.exe`, "a b"}, `"C:\Program Files\x.exe" "a b"`},
+		{[]string{"prog", ""}, `prog ""`},
+		{[]string{"prog", `a"b`}, `prog a\"b`},
+		{[]string{"prog", `a\b`, `a\\"b`}, `prog a\b a\\\\\"b`},
+		{[]string{"prog", `dir\`, `with space\`}, `prog dir\ "with space\\"`},
+		{[]string{`C:\dir\prog.exe`, `\\server\share`}, `C:\dir\prog.exe \\server\share`},
+	}
+	for _, tt := range tests {
+		got, err := I组合Windows命令行(tt.args)
+		if err != nil {
+			t.Errorf("I组合Windows命令行(%q): %v", tt.args, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("I组合Windows命令行(%q) = %s; want %s", tt.args, got, tt.want)
+		}
+		if back := I解析Windows命令行(got); !reflect.DeepEqual(back, tt.args) {
+			t.Errorf("I解析Windows命令行(%s) = %q; want %q", got, back, tt.args)
+		}
+	}
+	if _, err := I组合Windows命令行([]string{`a"b`}); err == nil {
+		t.Error(`I组合Windows命令行 accepted a program name containing '"'`)
+	}
+}
+
+func TestParseWindowsCommandLine(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{``, nil},
+		{`prog`, []string{"prog"}},
+		{`"C:\a b\"c d`, []string{`C:\a b\`, "c", "d"}},
+		{`prog  a	b `, []string{"prog", "a", "b"}},
+		{`prog "a b" c"d e"f`, []string{"prog", "a b", "cd ef"}},
+		{`prog a\\\"b a\\"b c"`, []string{"prog", `a\"b`, `a\b c`}},
+		{`prog "a""b"`, []string{"prog", `a"b`}},
+	}
+	for _, tt := range tests {
+		if got := I解析Windows命令行(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("I解析Windows命令行(%s) = %q; want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEscapeCmdArg(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"a b", `^"a b^"`},
+		{"a&b|c", "a^&b^|c"},
+		{"%PATH%", "^%PATH^%"},
+		{`say "hi" > x`, `^"say \^"hi\^" ^> x^"`},
+	}
+	for _, tt := range tests {
+		if got := I转义cmd参数(tt.in); got != tt.want {
+			t.Errorf("I转义cmd参数(%q) = %s; want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEscapeBatchArg(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"", `""`},
+		{`C:\dir\`, `"C:\dir\\"`},
+		{"a b", `"a b"`},
+		{`a"b`, `"a""b"`},
+		{"100%", `"100%%cd:~,%"`},
+		{"a&b", `"a&b"`},
+	}
+	for _, tt := range tests {
+		got, err := I转义批处理参数(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("I转义批处理参数(%q) = %s, %v; want %s", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := I转义批处理参数("a\nb"); err == nil {
+		t.Error("I转义批处理参数 accepted a newline")
+	}
+
+	line, err := I组合批处理命令行(`C:\x\run.bat`, []string{"a b", "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `cmd.exe /d /e:ON /v:OFF /c ""C:\x\run.bat" "a b" c"`; line != want {
+		t.Errorf("I组合批处理命令行 = %s; want %s", line, want)
+	}
+}
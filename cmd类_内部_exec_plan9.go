@@ -4,12 +4,17 @@
 
 package cmd类
 
-// skipStdinCopyError 可选地指定一个函数，该函数报告是否应忽略提供的stdin复制错误。
-//func skipStdinCopyError(err error) bool {
-//	// 如果程序成功完成，则忽略复制到stdin的挂起错误，否则将忽略。
-//	// See Issue 35753.
-//	pe, ok := err.(*fs.PathError)
-//	return ok &&
-//		pe.Op == "write" && pe.Path == "|1" &&
-//		pe.Err.Error() == "i/o on hungup channel"
-//}
+import (
+	"errors"
+	"io/fs"
+)
+
+// skipStdinCopyError 报告err是否是向标准输入管道写入时遇到的挂起错误（管道另一端已关闭）。
+func skipStdinCopyError(err error) bool {
+	// 如果程序成功完成，则忽略复制到stdin的挂起错误，否则将忽略。
+	// See Issue 35753.
+	var pe *fs.PathError
+	return errors.As(err, &pe) &&
+		pe.Op == "write" && pe.Path == "|1" &&
+		pe.Err.Error() == "i/o on hungup channel"
+}
@@ -6,11 +6,17 @@
 
 package cmd类
 
-// skipStdinCopyError 可选地指定一个函数，该函数报告是否应忽略提供的stdin复制错误。
-//func skipStdinCopyError(err error) bool {
-//	// 如果程序成功完成，则忽略EPIPE错误，否则复制到stdin。见问题9173。
-//	pe, ok := err.(*fs.PathError)
-//	return ok &&
-//		pe.Op == "write" && pe.Path == "|1" &&
-//		pe.Err == syscall.EPIPE
-//}
+import (
+	"errors"
+	"io/fs"
+	"syscall"
+)
+
+// skipStdinCopyError 报告err是否是向标准输入管道写入时遇到的EPIPE，即子进程已经关闭了读取端。
+func skipStdinCopyError(err error) bool {
+	// 如果程序成功完成，则忽略EPIPE错误，否则复制到stdin。见问题9173。
+	var pe *fs.PathError
+	return errors.As(err, &pe) &&
+		pe.Op == "write" && pe.Path == "|1" &&
+		pe.Err == syscall.EPIPE
+}
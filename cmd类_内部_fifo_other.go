@@ -0,0 +1,23 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package cmd类
+
+import (
+	"errors"
+	"os"
+	"runtime"
+)
+
+var errNoFifo = errors.New("exec: named pipes are not supported on " + runtime.GOOS)
+
+func mkfifo(path string) error {
+	return &os.PathError{Op: "mkfifo", Path: path, Err: errNoFifo}
+}
+
+func openFifoPartner(path string) (*os.File, error) {
+	return nil, &os.PathError{Op: "open", Path: path, Err: errNoFifo}
+}
+
+func isFifoBrokenPipe(err error) bool {
+	return false
+}
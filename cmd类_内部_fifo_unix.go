@@ -0,0 +1,27 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package cmd类
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+func mkfifo(path string) error {
+	if err := syscall.Mkfifo(path, 0600); err != nil {
+		return &os.PathError{Op: "mkfifo", Path: path, Err: err}
+	}
+	return nil
+}
+
+// openFifoPartner 以不阻塞的方式同时打开命名管道的读写两端，
+// 使另一个协程中阻塞在打开该命名管道上的调用得以返回。
+func openFifoPartner(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_RDWR|syscall.O_NONBLOCK, 0)
+}
+
+// isFifoBrokenPipe 报告err是否是写入读端已全部关闭的命名管道时产生的错误。
+func isFifoBrokenPipe(err error) bool {
+	return errors.Is(err, syscall.EPIPE)
+}
@@ -0,0 +1,7 @@
+//go:build !unix
+
+package cmd类
+
+import "os"
+
+var defaultForwardSignals = []os.Signal{os.Interrupt}
@@ -0,0 +1,10 @@
+//go:build unix
+
+package cmd类
+
+import (
+	"os"
+	"syscall"
+)
+
+var defaultForwardSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP}
@@ -0,0 +1,78 @@
+package cmd类
+
+import (
+	"io"
+	"os"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+// memfdCreateTrap 是各架构上memfd_create的系统调用号。syscall包并未为所有架构导出它。
+var memfdCreateTrap = map[string]uintptr{
+	"386":      356,
+	"amd64":    319,
+	"arm":      385,
+	"arm64":    279,
+	"loong64":  279,
+	"mips":     4354,
+	"mipsle":   4354,
+	"mips64":   5314,
+	"mips64le": 5314,
+	"ppc64":    360,
+	"ppc64le":  360,
+	"riscv64":  279,
+	"s390x":    350,
+}[runtime.GOARCH]
+
+const (
+	_MFD_CLOEXEC       = 0x1
+	_MFD_ALLOW_SEALING = 0x2
+
+	_F_ADD_SEALS   = 1024 + 9
+	_F_SEAL_SEAL   = 0x1
+	_F_SEAL_SHRINK = 0x2
+	_F_SEAL_GROW   = 0x4
+	_F_SEAL_WRITE  = 0x8
+	_F_SEAL_ALL    = _F_SEAL_SEAL | _F_SEAL_SHRINK | _F_SEAL_GROW | _F_SEAL_WRITE
+)
+
+const memfdStdinName = "cmd类-stdin"
+
+// newMemFile 返回内容为data、已封印且偏移量位于开头的匿名memfd。
+// 内核不支持memfd（或被seccomp等禁止）时退回 tempMemFile。
+func newMemFile(data []byte) (*os.File, func(), error) {
+	f, err := memfdFile(data)
+	if err != nil {
+		return tempMemFile(data)
+	}
+	return f, nil, nil
+}
+
+func memfdFile(data []byte) (*os.File, error) {
+	if memfdCreateTrap == 0 {
+		return nil, syscall.ENOSYS
+	}
+	name, err := syscall.BytePtrFromString(memfdStdinName)
+	if err != nil {
+		return nil, err
+	}
+	fd, _, errno := syscall.Syscall(memfdCreateTrap, uintptr(unsafe.Pointer(name)), _MFD_CLOEXEC|_MFD_ALLOW_SEALING, 0)
+	if errno != 0 {
+		return nil, os.NewSyscallError("memfd_create", errno)
+	}
+	f := os.NewFile(fd, memfdStdinName)
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, _, errno := syscall.Syscall(syscall.SYS_FCNTL, fd, _F_ADD_SEALS, _F_SEAL_ALL); errno != 0 {
+		f.Close()
+		return nil, os.NewSyscallError("fcntl", errno)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
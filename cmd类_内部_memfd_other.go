@@ -0,0 +1,10 @@
+//go:build !linux
+
+package cmd类
+
+import "os"
+
+// newMemFile 返回内容为data的只读临时文件，见 tempMemFile。
+func newMemFile(data []byte) (*os.File, func(), error) {
+	return tempMemFile(data)
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package cmd类
+
+import (
+	"errors"
+	"os"
+	"runtime"
+)
+
+// 没有SIGSTOP和SIGCONT的系统不支持暂停。
+var stopSignal, contSignal os.Signal
+
+var errPauseUnsupported = errors.New("exec: pausing commands is not supported on " + runtime.GOOS)
@@ -0,0 +1,12 @@
+//go:build unix
+
+package cmd类
+
+import (
+	"os"
+	"syscall"
+)
+
+var stopSignal, contSignal os.Signal = syscall.SIGSTOP, syscall.SIGCONT
+
+var errPauseUnsupported error
@@ -0,0 +1,20 @@
+//go:build !unix
+
+package cmd类
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+var errNoProcessGroup = errors.New("exec: process groups not supported on " + runtime.GOOS)
+
+func setProcessGroup(c *exec.Cmd, mode ProcessGroup模式) error {
+	return errNoProcessGroup
+}
+
+func signalGroup(pgid int, sig os.Signal) error {
+	return errNoProcessGroup
+}
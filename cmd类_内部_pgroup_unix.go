@@ -0,0 +1,43 @@
+//go:build unix
+
+package cmd类
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup 按mode设置c.SysProcAttr。
+// 修改的是一份副本，以免影响调用方可能共用的 SysProcAttr。
+func setProcessGroup(c *exec.Cmd, mode ProcessGroup模式) error {
+	var a syscall.SysProcAttr
+	if c.SysProcAttr != nil {
+		a = *c.SysProcAttr
+	}
+	switch mode {
+	case ProcessGroup_新进程组:
+		a.Setpgid = true
+		a.Pgid = 0
+	case ProcessGroup_新会话:
+		// setsid之后不能再setpgid。
+		a.Setsid = true
+		a.Setpgid = false
+	}
+	c.SysProcAttr = &a
+	return nil
+}
+
+// signalGroup 向进程组pgid发送sig。
+func signalGroup(pgid int, sig os.Signal) error {
+	s, ok := sig.(syscall.Signal)
+	if !ok {
+		return errors.New("exec: unsupported signal type")
+	}
+	err := syscall.Kill(-pgid, s)
+	if err == syscall.ESRCH {
+		return os.ErrProcessDone
+	}
+	return err
+}
@@ -0,0 +1,176 @@
+package cmd类
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// pidfdOpenTrap 和 pidfdSendSignalTrap 是各架构上pidfd_open和pidfd_send_signal的系统调用号。
+// 它们是在统一编号之后加入的，只有MIPS带有ABI偏移。
+var pidfdOpenTrap, pidfdSendSignalTrap = func() (uintptr, uintptr) {
+	switch runtime.GOARCH {
+	case "mips", "mipsle":
+		return 4434, 4424
+	case "mips64", "mips64le":
+		return 5434, 5424
+	}
+	return 434, 424
+}()
+
+const (
+	_P_PID   = 1
+	_P_PIDFD = 3
+)
+
+// watchesExit 报告 watchExit 是否会自己关闭通道。
+const watchesExit = true
+
+// preparePidfd 让启动时通过CLONE_PIDFD取得pidfd。调用方已设置 SysProcAttr.PidFD 时使用调用方的。
+func (c *Cmd) preparePidfd() {
+	attr := c.Cmd父类.SysProcAttr
+	if attr != nil && attr.PidFD != nil {
+		c.pidfd = attr.PidFD
+		c.ownPidfd = false
+		return
+	}
+	// 复制一份，以免修改调用方可能共用的 SysProcAttr。
+	var a syscall.SysProcAttr
+	if attr != nil {
+		a = *attr
+	}
+	fd := -1
+	a.PidFD = &fd
+	c.Cmd父类.SysProcAttr = &a
+	c.pidfd = &fd
+	c.ownPidfd = true
+}
+
+// openPidfd 在启动后CLONE_PIDFD未能取得pidfd时改用pidfd_open。
+// 子进程在被回收之前不会消失，因此这里不存在进程号重用的竞争。
+func (c *Cmd) openPidfd() {
+	c.pidMu.Lock()
+	defer c.pidMu.Unlock()
+	if c.pidfd == nil || *c.pidfd >= 0 {
+		return
+	}
+	fd, _, errno := syscall.Syscall(pidfdOpenTrap, uintptr(c.Cmd父类.Process.Pid), 0, 0)
+	if errno == 0 {
+		syscall.CloseOnExec(int(fd))
+		*c.pidfd = int(fd)
+	}
+}
+
+// signalPidfd 通过pidfd发送信号。
+func signalPidfd(fd int, sig os.Signal) error {
+	s, ok := sig.(syscall.Signal)
+	if !ok {
+		return errors.New("exec: unsupported signal type")
+	}
+	_, _, errno := syscall.Syscall6(pidfdSendSignalTrap, uintptr(fd), uintptr(s), 0, 0, 0, 0)
+	switch errno {
+	case 0:
+		return nil
+	case syscall.ESRCH:
+		return os.ErrProcessDone
+	}
+	return errno
+}
+
+// watchExit 在进程pid退出后关闭ch，调用时持有 pidMu，fd在此期间有效。
+// 有pidfd时把另一个pidfd交给运行时的网络轮询器等待可读，否则在协程中阻塞于不回收进程的waitid。
+func watchExit(pid, fd int, ch chan struct{}) {
+	var f *os.File
+	if fd >= 0 {
+		// 不能用fd的副本：O_NONBLOCK属于dup共享的打开文件描述，会使 os.Process.Wait 的waitid返回EAGAIN。
+		// 按进程号另开的pidfd在fd确认进程尚未被回收时指向同一进程，进程号不可能已被重用。
+		nfd, _, errno := syscall.Syscall(pidfdOpenTrap, uintptr(pid), 0, 0)
+		if errno == 0 {
+			if signalPidfd(fd, syscall.Signal(0)) != nil {
+				// 进程已被回收，当然也已退出。
+				closeFd(int(nfd))
+				close(ch)
+				return
+			}
+			syscall.CloseOnExec(int(nfd))
+			syscall.SetNonblock(int(nfd), true)
+			f = os.NewFile(nfd, "pidfd")
+		}
+	}
+	go func() {
+		defer close(ch)
+		if f != nil {
+			defer f.Close()
+			if waitPidfd(f) {
+				return
+			}
+		}
+		waitExited(_P_PID, pid)
+	}()
+}
+
+// pidfdRecheck 是等待pidfd时重新检查进程是否已退出的间隔。
+// 内核在进程退出时先唤醒pidfd的等待者、后设置退出状态，在这之间加入轮询的pidfd可能收不到通知。
+const pidfdRecheck = 500 * time.Millisecond
+
+// waitPidfd 等待pidfd f指向的进程退出，不能用pidfd等待时返回false。
+func waitPidfd(f *os.File) bool {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return false
+	}
+	for {
+		f.SetReadDeadline(time.Now().Add(pidfdRecheck))
+		err = rc.Read(func(s uintptr) bool {
+			return pidfdReadable(int(s))
+		})
+		if !errors.Is(err, os.ErrDeadlineExceeded) {
+			break
+		}
+	}
+	if err == nil {
+		return true
+	}
+	// 运行时不能轮询这个pidfd，改为阻塞于waitid（Linux 5.4以前不支持P_PIDFD）。
+	ok := false
+	rc.Control(func(s uintptr) {
+		syscall.SetNonblock(int(s), false)
+		ok = waitExited(_P_PIDFD, int(s))
+	})
+	return ok
+}
+
+// pidfdReadable 报告pidfd当前是否可读，即进程是否已经退出。
+func pidfdReadable(fd int) bool {
+	pfd := struct {
+		fd      int32
+		events  int16
+		revents int16
+	}{fd: int32(fd), events: 0x1} // POLLIN
+	var ts syscall.Timespec
+	n, _, errno := syscall.Syscall6(syscall.SYS_PPOLL, uintptr(unsafe.Pointer(&pfd)), 1, uintptr(unsafe.Pointer(&ts)), 0, 0, 0)
+	return errno == 0 && n == 1 && pfd.revents&0x1 != 0
+}
+
+// waitExited 用不回收进程的waitid等待进程退出，idtype不受支持时返回false。
+func waitExited(idtype, id int) bool {
+	var info [128]byte
+	for {
+		_, _, errno := syscall.Syscall6(syscall.SYS_WAITID, uintptr(idtype), uintptr(id), uintptr(unsafe.Pointer(&info)), syscall.WEXITED|syscall.WNOWAIT, 0, 0)
+		if errno != syscall.EINTR {
+			return errno != syscall.EINVAL
+		}
+	}
+}
+
+// waitUnreaped 阻塞到进程pid退出，不回收它，因此pid在此期间不会被重用。
+func waitUnreaped(pid int) {
+	waitExited(_P_PID, pid)
+}
+
+func closeFd(fd int) {
+	syscall.Close(fd)
+}
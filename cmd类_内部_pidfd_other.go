@@ -0,0 +1,25 @@
+//go:build !linux
+
+package cmd类
+
+import (
+	"errors"
+	"os"
+)
+
+// 其他系统没有pidfd，退出通知的通道由 closePidfd 在进程被回收后关闭。
+const watchesExit = false
+
+func (c *Cmd) preparePidfd() {}
+
+func (c *Cmd) openPidfd() {}
+
+func signalPidfd(fd int, sig os.Signal) error {
+	return errors.New("exec: pidfd not supported")
+}
+
+func watchExit(pid, fd int, ch chan struct{}) {}
+
+func waitUnreaped(pid int) {}
+
+func closeFd(fd int) {}
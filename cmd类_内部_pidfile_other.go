@@ -0,0 +1,21 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package cmd类
+
+import (
+	"errors"
+	"os"
+	"runtime"
+)
+
+var errLocked = errors.New("exec: file is locked")
+
+const haveFlock = false
+
+func lockFile(f *os.File) error {
+	return errors.New("exec: pidfiles are not supported on " + runtime.GOOS)
+}
+
+func processAlive(pid int) bool {
+	return false
+}
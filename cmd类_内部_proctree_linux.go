@@ -0,0 +1,246 @@
+package cmd类
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// procStat 是/proc/PID/stat中我们关心的字段。
+type procStat struct {
+	state byte
+	ppid  int
+	start uint64 // 启动时间，用于识别进程号重用
+	cpu   uint64 // 进程及其已回收的子进程消耗的CPU时间（时钟周期）
+}
+
+// readProcStat 读取进程pid的状态，进程不存在时返回错误。
+func readProcStat(pid int) (procStat, error) {
+	b, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return procStat{}, err
+	}
+	// 进程名可能含有空格和括号，以最后一个右括号为准。
+	s := string(b)
+	i := strings.LastIndexByte(s, ')')
+	if i < 0 {
+		return procStat{}, errors.New("exec: malformed /proc stat")
+	}
+	f := strings.Fields(s[i+1:])
+	// f[0]是第3个字段state，启动时间是第22个字段。
+	if len(f) < 20 {
+		return procStat{}, errors.New("exec: malformed /proc stat")
+	}
+	ppid, err1 := strconv.Atoi(f[1])
+	start, err2 := strconv.ParseUint(f[19], 10, 64)
+	if err1 != nil || err2 != nil {
+		return procStat{}, errors.New("exec: malformed /proc stat")
+	}
+	// 第14到17个字段是utime、stime、cutime和cstime。
+	var cpu uint64
+	for _, s := range f[11:15] {
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return procStat{}, errors.New("exec: malformed /proc stat")
+		}
+		cpu += n
+	}
+	return procStat{state: f[0][0], ppid: ppid, start: start, cpu: cpu}, nil
+}
+
+// procChildren 扫描/proc，返回各进程的子进程号。
+func procChildren() (map[int][]int, error) {
+	d, err := os.Open("/proc")
+	if err != nil {
+		return nil, err
+	}
+	names, err := d.Readdirnames(-1)
+	d.Close()
+	if err != nil {
+		return nil, err
+	}
+	children := make(map[int][]int)
+	for _, name := range names {
+		pid, err := strconv.Atoi(name)
+		if err != nil {
+			continue
+		}
+		st, err := readProcStat(pid)
+		if err != nil {
+			continue
+		}
+		children[st.ppid] = append(children[st.ppid], pid)
+	}
+	return children, nil
+}
+
+// procTree 是被冻结的进程树。
+type procTree struct {
+	pids  []int // 按发现顺序
+	start map[int]uint64
+}
+
+// alive 报告进程pid是否仍是最初发现的那个进程且尚未退出。
+func (t *procTree) alive(pid int) bool {
+	st, err := readProcStat(pid)
+	return err == nil && st.start == t.start[pid] && st.state != 'Z' && st.state != 'X'
+}
+
+// signal 向进程pid发送sig，进程已不是原来的进程时不发送。
+func (t *procTree) signal(pid int, sig syscall.Signal) {
+	if t.alive(pid) {
+		syscall.Kill(pid, sig)
+	}
+}
+
+// freeze 冻结树中仍存活进程的全部后代，直到进程树不再变化。
+func (t *procTree) freeze() error {
+	for {
+		for _, pid := range t.pids {
+			t.signal(pid, syscall.SIGSTOP)
+		}
+		t.waitStopped()
+		children, err := procChildren()
+		if err != nil {
+			return err
+		}
+		grew := false
+		for i := 0; i < len(t.pids); i++ {
+			pid := t.pids[i]
+			if !t.alive(pid) {
+				continue
+			}
+			for _, child := range children[pid] {
+				if _, ok := t.start[child]; ok {
+					continue
+				}
+				st, err := readProcStat(child)
+				if err != nil || st.ppid != pid {
+					continue
+				}
+				t.start[child] = st.start
+				t.pids = append(t.pids, child)
+				grew = true
+			}
+		}
+		if !grew {
+			return nil
+		}
+	}
+}
+
+// waitStopped 短暂等待SIGSTOP生效，此后进程不会再创建子进程。
+func (t *procTree) waitStopped() {
+	deadline := time.Now().Add(time.Second)
+	for _, pid := range t.pids {
+		for time.Now().Before(deadline) {
+			st, err := readProcStat(pid)
+			if err != nil || st.start != t.start[pid] || strings.IndexByte("TtZX", st.state) >= 0 {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+// anyAlive 报告树中是否还有进程存活。
+func (t *procTree) anyAlive() bool {
+	for _, pid := range t.pids {
+		if t.alive(pid) {
+			return true
+		}
+	}
+	return false
+}
+
+// killTree 实现 I终止进程树。
+func killTree(root int, steps []KillStep) ([]int, error) {
+	if len(steps) == 0 {
+		steps = []KillStep{{syscall.SIGTERM, 2 * time.Second}, {syscall.SIGKILL, 0}}
+	}
+	for _, step := range steps {
+		if _, ok := step.Signal.(syscall.Signal); !ok {
+			return nil, errors.New("exec: unsupported signal type")
+		}
+	}
+	st, err := readProcStat(root)
+	if err != nil {
+		return nil, err
+	}
+	t := &procTree{pids: []int{root}, start: map[int]uint64{root: st.start}}
+	signaled := make(map[int]bool)
+	for _, step := range steps {
+		if err := t.freeze(); err != nil {
+			return nil, err
+		}
+		sig := step.Signal.(syscall.Signal)
+		for _, pid := range t.pids {
+			if t.alive(pid) {
+				syscall.Kill(pid, sig)
+				signaled[pid] = true
+			}
+		}
+		if sig != syscall.SIGKILL && sig != syscall.SIGSTOP {
+			for _, pid := range t.pids {
+				t.signal(pid, syscall.SIGCONT)
+			}
+		}
+		deadline := time.Now().Add(step.Wait)
+		for t.anyAlive() && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if !t.anyAlive() {
+			break
+		}
+	}
+	var pids []int
+	for _, pid := range t.pids {
+		if signaled[pid] {
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}
+
+// haveProcStat 报告 procStartTime 是否可用。
+const haveProcStat = true
+
+// procStartTime 返回进程pid的启动时间，用于识别进程号重用。僵尸进程视为不存在。
+func procStartTime(pid int) (uint64, bool) {
+	st, err := readProcStat(pid)
+	if err != nil || st.state == 'Z' || st.state == 'X' {
+		return 0, false
+	}
+	return st.start, true
+}
+
+// procTreeCPU 返回进程root及其全部后代消耗的CPU时间之和（时钟周期）。
+func procTreeCPU(root int) (uint64, bool) {
+	st, err := readProcStat(root)
+	if err != nil {
+		return 0, false
+	}
+	children, err := procChildren()
+	if err != nil {
+		return 0, false
+	}
+	total := st.cpu
+	queue := children[root]
+	seen := map[int]bool{root: true}
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = queue[1:]
+		if seen[pid] {
+			continue // 扫描期间进程号被重用可能造成环
+		}
+		seen[pid] = true
+		if st, err := readProcStat(pid); err == nil {
+			total += st.cpu
+		}
+		queue = append(queue, children[pid]...)
+	}
+	return total, true
+}
@@ -0,0 +1,22 @@
+//go:build !linux
+
+package cmd类
+
+import (
+	"errors"
+	"runtime"
+)
+
+func killTree(root int, steps []KillStep) ([]int, error) {
+	return nil, errors.New("exec: process tree kill is not supported on " + runtime.GOOS)
+}
+
+const haveProcStat = false
+
+func procStartTime(pid int) (uint64, bool) {
+	return 0, false
+}
+
+func procTreeCPU(root int) (uint64, bool) {
+	return 0, false
+}
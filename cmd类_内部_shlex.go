@@ -0,0 +1,261 @@
+package cmd类
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ParseError 报告命令行中无法安全解析的位置。
+type ParseError struct {
+	// Input 是被解析的命令行。
+	Input string
+	// Offset 是出错处在 Input 中的字节偏移。
+	Offset int
+	// Msg 描述错误。
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	return "exec: parse " + strconv.Quote(e.Input) + ": offset " + strconv.Itoa(e.Offset) + ": " + e.Msg
+}
+
+type shTokenKind int
+
+const (
+	shWord  shTokenKind = iota
+	shPipe              // |
+	shRedir             // [n]<, [n]>, [n]>>, [n]>&m
+)
+
+// shToken 是 shLexer 产生的一个词法单元。
+type shToken struct {
+	kind shTokenKind
+	pos  int
+
+	// shWord
+	word   string
+	assign int // word 以 NAME= 开头且NAME未加引号时为'='的下标，否则为-1
+
+	// shRedir
+	fd    int    // 被重定向的描述符
+	op    string // "<", ">", ">>", ">&"
+	dupFd int    // op为">&"时的目标描述符
+}
+
+// shLexer 按POSIX shell的引用规则切分命令行。
+//
+// 严格模式下它只接受可以不经shell安全执行的子集：遇到参数展开、命令替换、
+// glob、控制操作符等会改变含义的语法时报错，而不是原样保留。
+// 非严格模式用于 I分割参数，只识别引用和转义，其余字符都属于词。
+type shLexer struct {
+	in     string
+	pos    int
+	strict bool
+	toks   []shToken
+}
+
+func (l *shLexer) errorf(pos int, msg string) error {
+	return &ParseError{Input: l.in, Offset: pos, Msg: msg}
+}
+
+// lex 切分整个输入。
+func (l *shLexer) lex() ([]shToken, error) {
+	for {
+		for l.pos < len(l.in) && (l.in[l.pos] == ' ' || l.in[l.pos] == '\t' || (!l.strict && l.in[l.pos] == '\n')) {
+			l.pos++
+		}
+		if l.pos >= len(l.in) {
+			return l.toks, nil
+		}
+		if err := l.next(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// next 读取一个词法单元。调用时l.pos指向非空白字符。
+func (l *shLexer) next() error {
+	start := l.pos
+	if l.strict {
+		switch c := l.in[l.pos]; c {
+		case '|':
+			if strings.HasPrefix(l.in[l.pos:], "||") {
+				return l.errorf(l.pos, "unsupported operator ||")
+			}
+			l.pos++
+			l.toks = append(l.toks, shToken{kind: shPipe, pos: start})
+			return nil
+		case '<', '>':
+			return l.redir(start, -1)
+		case '#':
+			return l.errorf(l.pos, "unsupported comment")
+		case '~':
+			return l.errorf(l.pos, "unsupported tilde expansion")
+		}
+	}
+
+	var b strings.Builder
+	assign := -1
+	literal := true // 目前为止的词是否全部由未加引号的字符组成
+	for l.pos < len(l.in) {
+		c := l.in[l.pos]
+		switch {
+		case c == ' ' || c == '\t' || (!l.strict && c == '\n'):
+			l.word(start, &b, assign, literal)
+			return nil
+		case c == '\'':
+			end := strings.IndexByte(l.in[l.pos+1:], '\'')
+			if end < 0 {
+				return l.errorf(l.pos, "unterminated single quote")
+			}
+			b.WriteString(l.in[l.pos+1 : l.pos+1+end])
+			l.pos += end + 2
+			literal = false
+		case c == '"':
+			if err := l.doubleQuoted(&b); err != nil {
+				return err
+			}
+			literal = false
+		case c == '\\':
+			if l.pos+1 >= len(l.in) {
+				return l.errorf(l.pos, "trailing backslash")
+			}
+			if l.in[l.pos+1] != '\n' {
+				b.WriteByte(l.in[l.pos+1])
+				literal = false
+			}
+			l.pos += 2
+		case !l.strict:
+			b.WriteByte(c)
+			l.pos++
+		case c == '<' || c == '>':
+			// 紧挨着重定向操作符的纯数字词是描述符，例如 2>。
+			if w := b.String(); literal && w != "" && isDigits(w) {
+				fd, err := strconv.Atoi(w)
+				if err != nil || fd > 2 {
+					return l.errorf(start, "unsupported file descriptor "+w)
+				}
+				return l.redir(start, fd)
+			}
+			l.word(start, &b, assign, literal)
+			return nil
+		case c == '|':
+			l.word(start, &b, assign, literal)
+			return nil
+		case c == '$' || c == '`':
+			return l.errorf(l.pos, "unsupported expansion "+string(c))
+		case c == '*' || c == '?' || c == '[':
+			return l.errorf(l.pos, "unsupported glob pattern")
+		case c == ';' || c == '&' || c == '(' || c == ')' || c == '\n' || c == '\r':
+			return l.errorf(l.pos, "unsupported control operator "+strconv.QuoteRune(rune(c)))
+		default:
+			if c == '=' && literal && assign < 0 && isName(b.String()) {
+				assign = b.Len()
+			}
+			b.WriteByte(c)
+			l.pos++
+		}
+	}
+	l.word(start, &b, assign, literal)
+	return nil
+}
+
+// word 添加一个词。只由续行（反斜杠加换行）组成的词不算词，空引号则算作一个空词。
+func (l *shLexer) word(start int, b *strings.Builder, assign int, literal bool) {
+	if b.Len() == 0 && literal {
+		return
+	}
+	l.toks = append(l.toks, shToken{kind: shWord, pos: start, word: b.String(), assign: assign})
+}
+
+// doubleQuoted 读取双引号中的内容，l.pos指向开头的引号。
+func (l *shLexer) doubleQuoted(b *strings.Builder) error {
+	start := l.pos
+	l.pos++
+	for l.pos < len(l.in) {
+		c := l.in[l.pos]
+		switch {
+		case c == '"':
+			l.pos++
+			return nil
+		case c == '\\' && l.pos+1 < len(l.in):
+			// 双引号中反斜杠只转义 $ ` " \ 和换行。
+			switch n := l.in[l.pos+1]; n {
+			case '$', '`', '"', '\\':
+				b.WriteByte(n)
+				l.pos += 2
+			case '\n':
+				l.pos += 2
+			default:
+				b.WriteByte(c)
+				l.pos++
+			}
+		case l.strict && (c == '$' || c == '`'):
+			return l.errorf(l.pos, "unsupported expansion "+string(c))
+		default:
+			b.WriteByte(c)
+			l.pos++
+		}
+	}
+	return l.errorf(start, "unterminated double quote")
+}
+
+// redir 读取从l.pos开始的重定向操作符，fd为-1时取操作符的默认描述符。
+func (l *shLexer) redir(start, fd int) error {
+	tok := shToken{kind: shRedir, pos: start}
+	rest := l.in[l.pos:]
+	switch {
+	case strings.HasPrefix(rest, "<<"), strings.HasPrefix(rest, "<>"), strings.HasPrefix(rest, "<&"):
+		return l.errorf(l.pos, "unsupported redirection "+rest[:2])
+	case strings.HasPrefix(rest, ">>"):
+		tok.op = ">>"
+	case strings.HasPrefix(rest, ">&"):
+		tok.op = ">&"
+	case strings.HasPrefix(rest, ">|"):
+		tok.op = ">"
+		l.pos++ // >| 与 > 相同
+	default:
+		tok.op = rest[:1]
+	}
+	l.pos += len(tok.op)
+	if fd < 0 {
+		fd = 1
+		if tok.op == "<" {
+			fd = 0
+		}
+	}
+	if (tok.op == "<") != (fd == 0) {
+		return l.errorf(start, "unsupported redirection of file descriptor "+strconv.Itoa(fd))
+	}
+	tok.fd = fd
+	if tok.op == ">&" {
+		if l.pos >= len(l.in) || l.in[l.pos] < '1' || l.in[l.pos] > '2' ||
+			(l.pos+1 < len(l.in) && !strings.ContainsRune(" \t|<>", rune(l.in[l.pos+1]))) {
+			return l.errorf(start, "unsupported duplication; only >&1 and >&2 are allowed")
+		}
+		tok.dupFd = int(l.in[l.pos] - '0')
+		l.pos++
+	}
+	l.toks = append(l.toks, tok)
+	return nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// isName 报告s是否是合法的shell变量名。
+func isName(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || i > 0 && '0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,139 @@
+package cmd类
+
+import (
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const _PR_SET_CHILD_SUBREAPER = 36
+
+// reaper 是收养回收模式的进程级状态。
+var reaper struct {
+	ctl  sync.Mutex    // 串行化启用和停用
+	stop chan struct{} // 非nil表示回收协程正在运行
+	done chan struct{}
+
+	mu       sync.Mutex // 保护callback，回收协程也会获取
+	callback func(AdoptedProcess)
+}
+
+func setSubreaper(on bool) error {
+	v := uintptr(0)
+	if on {
+		v = 1
+	}
+	_, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, _PR_SET_CHILD_SUBREAPER, v, 0)
+	if errno != 0 {
+		return os.NewSyscallError("prctl", errno)
+	}
+	return nil
+}
+
+func enableSubreaper(callback func(AdoptedProcess)) error {
+	reaper.ctl.Lock()
+	defer reaper.ctl.Unlock()
+	reaper.mu.Lock()
+	reaper.callback = callback
+	reaper.mu.Unlock()
+	if reaper.stop != nil {
+		return nil
+	}
+	if err := setSubreaper(true); err != nil {
+		return err
+	}
+	reaper.stop = make(chan struct{})
+	reaper.done = make(chan struct{})
+	go reapLoop(reaper.stop, reaper.done)
+	return nil
+}
+
+func disableSubreaper() error {
+	reaper.ctl.Lock()
+	defer reaper.ctl.Unlock()
+	if reaper.stop == nil {
+		return nil
+	}
+	close(reaper.stop)
+	<-reaper.done
+	reaper.stop, reaper.done = nil, nil
+	return setSubreaper(false)
+}
+
+// reapLoop 在收到SIGCHLD时回收被收养的僵尸进程。信号可能合并，因此也定期检查。
+func reapLoop(stop, done chan struct{}) {
+	defer close(done)
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGCHLD)
+	defer signal.Stop(sigc)
+	tick := time.NewTicker(time.Second)
+	defer tick.Stop()
+	for {
+		reapAdopted()
+		select {
+		case <-stop:
+			return
+		case <-sigc:
+		case <-tick.C:
+		}
+	}
+}
+
+// reapAdopted 回收本进程的子进程中不属于任何 Cmd 的僵尸进程。
+// 只用wait4等待具体的进程号，不会收到 Cmd 的子进程的退出状态。
+// 扫描/proc时不持有 startGate，只在确认和回收每个僵尸进程时持有。
+func reapAdopted() {
+	type candidate struct {
+		pid  int
+		name string
+	}
+	var candidates []candidate
+	children, err := procChildren()
+	if err != nil {
+		return
+	}
+	for _, pid := range children[os.Getpid()] {
+		if isOwnChild(pid) {
+			continue
+		}
+		st, err := readProcStat(pid)
+		if err != nil || st.state != 'Z' {
+			continue
+		}
+		name, _ := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/comm")
+		candidates = append(candidates, candidate{pid, strings.TrimSpace(string(name))})
+	}
+
+	var reaped []AdoptedProcess
+	for _, p := range candidates {
+		// 扫描时刚启动、尚未登记的 Cmd 子进程在取得写锁后已经登记。
+		startGate.Lock()
+		var ws syscall.WaitStatus
+		wpid := -1
+		if !isOwnChild(p.pid) {
+			wpid, _ = syscall.Wait4(p.pid, &ws, syscall.WNOHANG, nil)
+		}
+		startGate.Unlock()
+		if wpid != p.pid {
+			continue
+		}
+		code := ws.ExitStatus()
+		if !ws.Exited() {
+			code = -1
+		}
+		reaped = append(reaped, AdoptedProcess{Pid: p.pid, Name: p.name, ExitCode: code})
+	}
+
+	reaper.mu.Lock()
+	callback := reaper.callback
+	reaper.mu.Unlock()
+	if callback != nil {
+		for _, p := range reaped {
+			callback(p)
+		}
+	}
+}
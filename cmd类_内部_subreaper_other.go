@@ -0,0 +1,16 @@
+//go:build !linux
+
+package cmd类
+
+import (
+	"errors"
+	"runtime"
+)
+
+func enableSubreaper(callback func(AdoptedProcess)) error {
+	return errors.New("exec: subreaper mode is not supported on " + runtime.GOOS)
+}
+
+func disableSubreaper() error {
+	return nil
+}
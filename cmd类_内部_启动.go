@@ -0,0 +1,72 @@
+package cmd类
+
+// beforeStart 在 Cmd父类.Start 之前调用，把 Cmd 上的各项设置落实到 Cmd父类 中。
+// 返回错误时不会启动进程，调用方随后须调用 startFailed 释放已分配的资源。
+func (c *Cmd) beforeStart() error {
+	if err := c.I展开通配符(); err != nil {
+		return err
+	}
+	if err := c.preparePidFile(); err != nil {
+		return err
+	}
+	if err := c.prepareProcessGroup(); err != nil {
+		return err
+	}
+	if err := c.prepareRedirects(); err != nil {
+		return err
+	}
+	if err := c.prepareOutput(); err != nil {
+		return err
+	}
+	if err := c.prepareStdin(); err != nil {
+		return err
+	}
+	if err := c.prepareSubsts(); err != nil {
+		return err
+	}
+	c.preparePidfd()
+	return nil
+}
+
+// startFailed 在 beforeStart 或 Cmd父类.Start 失败后调用，释放 beforeStart 分配的资源。
+func (c *Cmd) startFailed() {
+	c.closeRedirects()
+	c.abortStdin()
+	c.abortSubsts()
+	c.abortFifos()
+	c.abortOutput()
+	c.closePidFile()
+}
+
+// afterStart 在进程成功启动后调用。
+func (c *Cmd) afterStart() {
+	c.openPidfd()
+	c.startClock()
+	c.startOutput()
+	c.startForward()
+	c.closeRedirects()
+	c.startStdin()
+	c.startSubsts()
+	c.startFifos()
+}
+
+// afterWait 在 Cmd父类.Wait 返回后调用，等待各辅助协程结束并合并它们的错误。
+// 进程本身的错误优先于辅助协程的错误。
+func (c *Cmd) afterWait(err error) error {
+	c.stopForward()
+	if serr := c.waitStdin(); err == nil {
+		err = serr
+	}
+	if serr := c.waitSubsts(); err == nil {
+		err = serr
+	}
+	if serr := c.waitFifos(); err == nil {
+		err = serr
+	}
+	if serr := c.waitOutput(); err == nil {
+		err = serr
+	}
+	c.closePidfd()
+	c.closePidFile()
+	return c.stopClock(err)
+}
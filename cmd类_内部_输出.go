@@ -0,0 +1,192 @@
+package cmd类
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// 无输出超时和按输出行判断就绪都需要观察子进程写出的标准输出和标准错误。
+
+// watchesOutput 报告是否需要观察命令的输出。
+func (c *Cmd) watchesOutput() bool {
+	return c.idleTimeout > 0 || c.ready.needsOutput()
+}
+
+// observeOutput 处理子进程在stream（1为标准输出，2为标准错误）上写出的数据。
+func (c *Cmd) observeOutput(stream int, p []byte) {
+	if len(p) == 0 {
+		return
+	}
+	if c.idleTimeout > 0 {
+		c.touchIdle()
+	}
+	c.ready.scan(stream, p)
+}
+
+// outputWriter 把写入的数据交给 observeOutput 后再写入w。
+type outputWriter struct {
+	c      *Cmd
+	stream int
+	w      io.Writer
+}
+
+func (w *outputWriter) Write(p []byte) (int, error) {
+	w.c.observeOutput(w.stream, p)
+	return w.w.Write(p)
+}
+
+// prepareOutput 在需要观察输出时包装标准输出和标准错误。
+func (c *Cmd) prepareOutput() error {
+	if !c.watchesOutput() {
+		return nil
+	}
+	// 两者相同时 exec.Cmd 只创建一个管道，包装后仍须相同。
+	same := sameWriter(c.Cmd父类.Stdout, c.Cmd父类.Stderr)
+	stdout, err := c.wrapOutput(1, c.Cmd父类.Stdout)
+	if err != nil {
+		return err
+	}
+	stderr := stdout
+	if !same {
+		if stderr, err = c.wrapOutput(2, c.Cmd父类.Stderr); err != nil {
+			return err
+		}
+	}
+	c.Cmd父类.Stdout, c.Cmd父类.Stderr = stdout, stderr
+	return nil
+}
+
+// outputGrace 是命令被超时终止后继续复制输出的时长。
+// 仍持有输出的后代进程可能远比命令活得久，到期后不再等待它们。
+const outputGrace = 100 * time.Millisecond
+
+// wrapOutput 返回代替w交给 exec.Cmd 的写入器。
+//
+// 子进程总是写入本包创建的管道，由 outputCopy 把数据复制到w，使超时终止命令后可以停止复制（见 waitOutput）；
+// exec.Cmd 自己创建的管道由它的 Wait 等待，本包无法中止。
+// w是 *os.File 时复制到它的副本，因为w可能在启动后被关闭（例如 I取标准管道 的写入端或重定向打开的文件），
+// 而副本在子进程的输出结束时就关闭，读取 I取标准管道 的一方照常读到EOF。
+func (c *Cmd) wrapOutput(stream int, w io.Writer) (io.Writer, error) {
+	var d *os.File
+	switch f := w.(type) {
+	case nil:
+		w = io.Discard
+	case *os.File:
+		var err error
+		if d, err = dupFile(f); err != nil {
+			return nil, err
+		}
+		w = d
+	}
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		if d != nil {
+			d.Close()
+		}
+		return nil, err
+	}
+	c.outCopies = append(c.outCopies, &outputCopy{stream: stream, r: pr, child: pw, dst: w, dup: d})
+	return pw, nil
+}
+
+// outputCopy 把子进程写入管道的数据复制到原来的写入器。
+type outputCopy struct {
+	stream int
+	r      *os.File  // 本进程读取的一端
+	child  *os.File  // 子进程写入的一端，启动后关闭
+	dst    io.Writer // 原来的写入器，*os.File 换成它的副本dup
+	dup    *os.File  // 原来的 *os.File 的副本，复制结束时关闭
+	errc   chan error
+}
+
+// startOutput 在进程启动后开始复制输出。
+func (c *Cmd) startOutput() {
+	for _, p := range c.outCopies {
+		p.child.Close()
+		p.errc = make(chan error, 1)
+		go func(p *outputCopy) {
+			_, err := io.Copy(&outputWriter{c: c, stream: p.stream, w: p.dst}, p.r)
+			p.r.Close()
+			if p.dup != nil {
+				if cerr := p.dup.Close(); err == nil {
+					err = cerr
+				}
+			}
+			p.errc <- err
+		}(p)
+	}
+}
+
+// abortOutput 在启动失败后关闭 wrapOutput 创建的管道和副本。
+func (c *Cmd) abortOutput() {
+	for _, p := range c.outCopies {
+		p.r.Close()
+		p.child.Close()
+		if p.dup != nil {
+			p.dup.Close()
+		}
+	}
+	c.outCopies = nil
+}
+
+// waitOutput 等待复制输出的协程结束，返回第一个复制错误。
+//
+// 后代进程可能在命令退出后仍持有输出管道。命令被超时终止时最多再等待 outputGrace，
+// 设置了 Cmd父类.WaitDelay 时最多再等待这么久并返回 exec.ErrWaitDelay，与 exec.Cmd 相同；
+// 到期后关闭管道的读取端，丢弃后代进程随后的输出。
+func (c *Cmd) waitOutput() error {
+	if len(c.outCopies) == 0 {
+		return nil
+	}
+	c.clock.mu.Lock()
+	timedOut := c.clock.timedOut != nil
+	c.clock.mu.Unlock()
+	delay := c.Cmd父类.WaitDelay
+	if timedOut {
+		delay = outputGrace
+	}
+	var expired <-chan time.Time
+	if delay > 0 {
+		t := time.NewTimer(delay)
+		defer t.Stop()
+		expired = t.C
+	}
+	var copyErr error
+	abandoned := false
+	for _, p := range c.outCopies {
+		var err error
+		select {
+		case err = <-p.errc:
+		case <-expired:
+			abandoned = true
+			for _, q := range c.outCopies {
+				q.r.Close()
+			}
+			err = <-p.errc
+		}
+		if abandoned && errors.Is(err, os.ErrClosed) {
+			err = nil
+		}
+		if copyErr == nil {
+			copyErr = err
+		}
+	}
+	c.outCopies = nil
+	if abandoned && !timedOut && copyErr == nil {
+		copyErr = exec.ErrWaitDelay
+	}
+	return copyErr
+}
+
+// sameWriter 报告a和b是否是同一个写入器，动态类型不可比较时视为不同。
+func sameWriter(a, b io.Writer) (same bool) {
+	defer func() {
+		if recover() != nil {
+			same = false
+		}
+	}()
+	return a == b
+}
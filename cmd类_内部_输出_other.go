@@ -0,0 +1,13 @@
+//go:build !unix && !windows
+
+package cmd类
+
+import (
+	"errors"
+	"os"
+	"runtime"
+)
+
+func dupFile(f *os.File) (*os.File, error) {
+	return nil, errors.New("exec: observing *os.File output is not supported on " + runtime.GOOS)
+}
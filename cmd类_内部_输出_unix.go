@@ -0,0 +1,34 @@
+//go:build unix
+
+package cmd类
+
+import (
+	"os"
+	"syscall"
+)
+
+// dupFile 返回f的一个带close-on-exec标志的副本。
+func dupFile(f *os.File) (*os.File, error) {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+	fd := -1
+	var derr error
+	err = rc.Control(func(s uintptr) {
+		// 持有ForkLock，使并发启动的子进程不会继承尚未设置close-on-exec的副本。
+		syscall.ForkLock.RLock()
+		fd, derr = syscall.Dup(int(s))
+		if derr == nil {
+			syscall.CloseOnExec(fd)
+		}
+		syscall.ForkLock.RUnlock()
+	})
+	if err == nil {
+		err = derr
+	}
+	if err != nil {
+		return nil, os.NewSyscallError("dup", err)
+	}
+	return os.NewFile(uintptr(fd), f.Name()), nil
+}
@@ -0,0 +1,27 @@
+package cmd类
+
+import (
+	"os"
+	"syscall"
+)
+
+// dupFile 返回f的一个不可继承的副本。
+func dupFile(f *os.File) (*os.File, error) {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+	var h syscall.Handle
+	var derr error
+	err = rc.Control(func(s uintptr) {
+		p, _ := syscall.GetCurrentProcess()
+		derr = syscall.DuplicateHandle(p, syscall.Handle(s), p, &h, 0, false, syscall.DUPLICATE_SAME_ACCESS)
+	})
+	if err == nil {
+		err = derr
+	}
+	if err != nil {
+		return nil, os.NewSyscallError("DuplicateHandle", err)
+	}
+	return os.NewFile(uintptr(h), f.Name()), nil
+}
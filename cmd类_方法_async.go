@@ -0,0 +1,90 @@
+package cmd类
+
+import (
+	"errors"
+	"sync"
+)
+
+// AsyncHandle 是 I运行_异步句柄 返回的句柄，代表一个在后台运行并被等待的命令。
+// Done 可以与上下文、定时器等一起用在select中。
+type AsyncHandle struct {
+	c    *Cmd
+	done chan struct{}
+	err  error
+
+	mu        sync.Mutex
+	callbacks []func(*Cmd, error)
+	fired     bool // 回调都已执行完毕，此后注册的回调立即调用
+}
+
+// I运行_异步句柄 与 I运行_异步 一样启动命令，并在后台等待它，返回用于观察结果的句柄。
+// 命令的结果由句柄取得，不要再对它调用 I等待运行完成，请使用 Result。
+func (c *Cmd) I运行_异步句柄() (*AsyncHandle, error) {
+	if c == nil {
+		return nil, errors.New("cmd类对象为nil")
+	}
+	if err := c.I运行_异步(); err != nil {
+		return nil, err
+	}
+	w, err := c.backgroundWaiter()
+	if err != nil {
+		return nil, err
+	}
+	h := &AsyncHandle{c: c, done: make(chan struct{})}
+	go func() {
+		<-w.done
+		h.err = c.collect(w)
+		close(h.done)
+		h.runCallbacks()
+	}()
+	return h, nil
+}
+
+// Done 返回一个通道，命令退出并且 I等待运行完成 的各项收尾完成后关闭。
+func (h *AsyncHandle) Done() <-chan struct{} {
+	return h.done
+}
+
+// Result 等待命令结束，返回 I等待运行完成 的结果。可以多次调用，每次返回相同的结果。
+func (h *AsyncHandle) Result() error {
+	<-h.done
+	return h.err
+}
+
+// I取命令 返回句柄对应的命令。
+func (h *AsyncHandle) I取命令() *Cmd {
+	return h.c
+}
+
+// I添加退出回调 注册命令结束后调用的 回调，参数是命令和 Result 的结果。
+// 回调在 Done 关闭之后按注册顺序在同一个后台协程中依次调用，因此可以在回调中调用 Result；
+// 回调执行期间注册的回调排在已注册的回调之后，由同一个协程调用。
+// 命令已经结束并且已注册的回调都执行完毕后，注册的回调在调用方的协程中立即调用。
+func (h *AsyncHandle) I添加退出回调(回调 func(*Cmd, error)) {
+	h.mu.Lock()
+	if !h.fired {
+		h.callbacks = append(h.callbacks, 回调)
+		h.mu.Unlock()
+		return
+	}
+	h.mu.Unlock()
+	回调(h.c, h.Result())
+}
+
+// runCallbacks 依次调用已注册的回调，直到没有新注册的回调为止。
+func (h *AsyncHandle) runCallbacks() {
+	for {
+		h.mu.Lock()
+		callbacks := h.callbacks
+		h.callbacks = nil
+		if len(callbacks) == 0 {
+			h.fired = true
+			h.mu.Unlock()
+			return
+		}
+		h.mu.Unlock()
+		for _, f := range callbacks {
+			f(h.c, h.err)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package cmd类
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// 本文件中的函数只做字符串处理，在任何系统上都可以调用，便于交叉编译的程序构造Windows命令行，
+// 例如作为 SysProcAttr.CmdLine 的值。
+
+// I组合Windows命令行 把 参数组 组合为一个命令行字符串，使用CommandLineToArgvW解析命令行的程序
+// （绝大多数程序）会得到与 参数组 相同的参数，I解析Windows命令行 是它的逆运算。
+// 这与Windows上 I设置命令 的组合方式相同。
+//
+// 第一个参数是程序名，按Windows的规则它只能用引号括起而不能转义，因此其中含有双引号时返回错误。
+// msiexec.exe、cmd.exe 和批处理文件使用其他规则，见 I转义cmd参数 和 I组合批处理命令行。
+func I组合Windows命令行(参数组 []string) (string, error) {
+	if len(参数组) == 0 {
+		return "", nil
+	}
+	name := 参数组[0]
+	if strings.ContainsRune(name, '"') {
+		return "", errors.New("exec: program name " + name + " contains a double quote")
+	}
+	var b []byte
+	if name == "" || strings.ContainsAny(name, " \t") {
+		b = append(b, '"')
+		b = append(b, name...)
+		b = append(b, '"')
+	} else {
+		b = append(b, name...)
+	}
+	for _, arg := range 参数组[1:] {
+		b = append(b, ' ')
+		b = appendEscapeArg(b, arg)
+	}
+	return string(b), nil
+}
+
+// I解析Windows命令行 按CommandLineToArgvW的规则把 命令行 分割为参数列表。
+//
+// 第一个参数是程序名：以双引号开头时取到下一个双引号为止，否则取到第一个空白为止，其中的反斜杠不作转义。
+// 其余参数中，2n个反斜杠加双引号表示n个反斜杠并切换引号状态，2n+1个反斜杠加双引号表示n个反斜杠和一个双引号，
+// 引号中连续的两个双引号表示一个双引号。
+func I解析Windows命令行(命令行 string) []string {
+	cmd := 命令行
+	if cmd == "" {
+		return nil
+	}
+	var args []string
+	if cmd[0] == '"' {
+		end := strings.IndexByte(cmd[1:], '"')
+		if end < 0 {
+			return []string{cmd[1:]}
+		}
+		args = append(args, cmd[1:1+end])
+		cmd = cmd[2+end:]
+	} else {
+		end := strings.IndexAny(cmd, " \t")
+		if end < 0 {
+			return []string{cmd}
+		}
+		args = append(args, cmd[:end])
+		cmd = cmd[end:]
+	}
+	for len(cmd) > 0 {
+		if cmd[0] == ' ' || cmd[0] == '\t' {
+			cmd = cmd[1:]
+			continue
+		}
+		var arg []byte
+		arg, cmd = readNextArg(cmd)
+		args = append(args, string(arg))
+	}
+	return args
+}
+
+// I转义cmd参数 转义一个参数，使其经过cmd.exe（例如 cmd /c 后面的命令行）处理后，
+// 目标程序按CommandLineToArgvW规则得到的仍是原来的 参数。
+//
+// 它先按 I组合Windows命令行 的规则引用参数，再在cmd.exe的每个元字符 ( ) % ! ^ " < > & | 前加 ^，
+// 使cmd.exe不会把其中任何部分当作重定向、管道或变量展开。
+// 目标是批处理文件时，cmd.exe会再次解析参数，应改用 I转义批处理参数。
+func I转义cmd参数(参数 string) string {
+	q := appendEscapeArg(nil, 参数)
+	b := make([]byte, 0, len(q)+8)
+	for _, c := range q {
+		if strings.IndexByte(`()%!^"<>&|`, c) >= 0 {
+			b = append(b, '^')
+		}
+		b = append(b, c)
+	}
+	return string(b)
+}
+
+// I转义批处理参数 转义一个传给批处理文件（.bat、.cmd）的参数。
+//
+// 除常见安全字符外的参数都用双引号括起，其中的双引号写作 ""，% 写作 %%cd:~,%
+// （一个展开为空串的变量引用，用来阻止变量展开），结尾的反斜杠加倍以免转义结束引号。
+// 批处理文件无法安全地接收回车、换行和NUL，参数中含有它们时返回错误。
+func I转义批处理参数(参数 string) (string, error) {
+	if strings.ContainsAny(参数, "\r\n\x00") {
+		return "", errors.New("exec: batch file argument " + strconv.Quote(参数) + " contains CR, LF or NUL")
+	}
+	quote := 参数 == "" || strings.HasSuffix(参数, `\`)
+	for i := 0; i < len(参数); i++ {
+		c := 参数[i]
+		if c < 0x80 && !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte(`#$*+-./:?@\_`, c) >= 0) {
+			quote = true
+		}
+	}
+	var b []byte
+	if quote {
+		b = append(b, '"')
+	}
+	slashes := 0
+	for i := 0; i < len(参数); i++ {
+		c := 参数[i]
+		switch c {
+		case '\\':
+			slashes++
+		case '"':
+			for ; slashes > 0; slashes-- {
+				b = append(b, '\\')
+			}
+		case '%':
+			b = append(b, "%%cd:~,"...)
+			slashes = 0
+		default:
+			slashes = 0
+		}
+		b = append(b, c)
+		if c == '"' {
+			b = append(b, '"')
+		}
+	}
+	if quote {
+		for ; slashes > 0; slashes-- {
+			b = append(b, '\\')
+		}
+		b = append(b, '"')
+	}
+	return string(b), nil
+}
+
+// I组合批处理命令行 返回经由cmd.exe运行 批处理文件 并传入 参数组 的完整命令行，
+// 可以作为 SysProcAttr.CmdLine 的值，同时把 Cmd父类.Path 设为cmd.exe的路径。
+// 每个参数都经过 I转义批处理参数 转义。
+func I组合批处理命令行(批处理文件 string, 参数组 []string) (string, error) {
+	if strings.ContainsAny(批处理文件, "\"\r\n\x00") {
+		return "", errors.New("exec: batch file name " + strconv.Quote(批处理文件) + " contains invalid characters")
+	}
+	var b strings.Builder
+	b.WriteString(`cmd.exe /d /e:ON /v:OFF /c ""`)
+	b.WriteString(strings.ReplaceAll(批处理文件, "%", "%%cd:~,%"))
+	b.WriteString(`"`)
+	for _, arg := range 参数组 {
+		q, err := I转义批处理参数(arg)
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(' ')
+		b.WriteString(q)
+	}
+	b.WriteString(`"`)
+	return b.String(), nil
+}
+
+// appendEscapeArg 按CommandLineToArgvW的规则引用s并追加到b。
+func appendEscapeArg(b []byte, s string) []byte {
+	if len(s) == 0 {
+		return append(b, `""`...)
+	}
+
+	needsBackslash := false
+	hasSpace := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"', '\\':
+			needsBackslash = true
+		case ' ', '\t':
+			hasSpace = true
+		}
+	}
+
+	if !needsBackslash && !hasSpace {
+		// 无需转义或引用。
+		return append(b, s...)
+	}
+	if !needsBackslash {
+		// 只需引用。
+		b = append(b, '"')
+		b = append(b, s...)
+		return append(b, '"')
+	}
+
+	if hasSpace {
+		b = append(b, '"')
+	}
+	slashes := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		default:
+			slashes = 0
+		case '\\':
+			slashes++
+		case '"':
+			for ; slashes > 0; slashes-- {
+				b = append(b, '\\')
+			}
+			b = append(b, '\\')
+		}
+		b = append(b, c)
+	}
+	if hasSpace {
+		for ; slashes > 0; slashes-- {
+			b = append(b, '\\')
+		}
+		b = append(b, '"')
+	}
+	return b
+}
+
+// readNextArg 按CommandLineToArgvW的规则从cmd开头读取一个参数（程序名之后的参数），并返回剩余部分。
+func readNextArg(cmd string) (arg []byte, rest string) {
+	var b []byte
+	var inquote bool
+	var nslash int
+	for ; len(cmd) > 0; cmd = cmd[1:] {
+		c := cmd[0]
+		switch c {
+		case ' ', '\t':
+			if !inquote {
+				return appendBSBytes(b, nslash), cmd[1:]
+			}
+		case '"':
+			b = appendBSBytes(b, nslash/2)
+			if nslash%2 == 0 {
+				// 引号中连续的两个双引号表示一个双引号，见
+				// http://daviddeley.com/autohotkey/parameters/parameters.htm 第5.2节。
+				if inquote && len(cmd) > 1 && cmd[1] == '"' {
+					b = append(b, c)
+					cmd = cmd[1:]
+				}
+				inquote = !inquote
+			} else {
+				b = append(b, c)
+			}
+			nslash = 0
+			continue
+		case '\\':
+			nslash++
+			continue
+		}
+		b = appendBSBytes(b, nslash)
+		nslash = 0
+		b = append(b, c)
+	}
+	return appendBSBytes(b, nslash), ""
+}
+
+// appendBSBytes 在b后追加n个反斜杠并返回b。
+func appendBSBytes(b []byte, n int) []byte {
+	for ; n > 0; n-- {
+		b = append(b, '\\')
+	}
+	return b
+}
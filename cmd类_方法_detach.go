@@ -0,0 +1,176 @@
+package cmd类
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DetachOptions 是 I分离运行 的选项。
+type DetachOptions struct {
+	// Stdout和Stderr是标准输出和标准错误追加写入的日志文件，不存在时创建。
+	// 为空时使用 /dev/null；Stderr与Stdout相同时两者共用一个文件。
+	// Cmd父类.Stdout 或 Cmd父类.Stderr 已设置为 *os.File 时忽略对应的选项。
+	Stdout string
+	Stderr string
+
+	// PidFile非空时启动前用 I打开PidFile 取得该pidfile的锁（已有运行中的实例时返回 *PidFileError），
+	// 启动后写入命令的进程号。锁的文件描述符作为一个额外的文件由命令继承，命令运行期间一直持有锁；
+	// 命令退出后文件留在原处，但会被 I打开PidFile 和 I读取PidFile 识别为过期。
+	PidFile string
+
+	// DoubleFork为true时经由一个中间进程启动命令，取得命令的进程号后终止中间进程，
+	// 使命令不是会话首进程，无法再获得控制终端，并且直接由init收养
+	// （当前进程用 I启用收养回收 成为收养回收者时由当前进程收养，并在命令退出后回收）。
+	// 此时 Cmd父类.Args[0] 不会传给命令。
+	//
+	// I设置超时、I设置PidFile 和 I设置信号转发 只能作用于中间进程，因此不能与DoubleFork同时使用，
+	// pidfile请改用PidFile选项。
+	DoubleFork bool
+}
+
+// I分离运行 在新会话（setsid）中启动命令并立即返回它的进程号，使命令在当前进程退出后继续运行，
+// 适合从命令行工具启动后台服务。调用方不需要也不能再调用 I等待运行完成。
+//
+// 未设置的标准输入为 /dev/null，标准输出和标准错误按 选项 重定向到日志文件或 /dev/null。
+// 命令不能依赖当前进程，因此 Cmd父类.Stdin、Cmd父类.Stdout 和 Cmd父类.Stderr 只能是nil或 *os.File。
+// 工作目录和umask不会改变，需要时请设置 Cmd父类.Dir。
+//
+// 不使用DoubleFork时命令仍是当前进程的子进程，本包在后台回收它，以免当前进程继续运行时留下僵尸进程。
+// 写入pidfile失败时命令已经启动，返回它的进程号和错误。仅Unix系统支持。
+func (c *Cmd) I分离运行(选项 DetachOptions) (int, error) {
+	if c == nil {
+		return 0, errors.New("cmd类对象为nil")
+	}
+	if c.Cmd父类.Process != nil {
+		return 0, errors.New("exec: already started")
+	}
+	for _, s := range []any{c.Cmd父类.Stdin, c.Cmd父类.Stdout, c.Cmd父类.Stderr} {
+		if _, ok := s.(*os.File); s != nil && !ok {
+			return 0, errors.New("exec: detached command needs nil or *os.File standard I/O")
+		}
+	}
+	if c.watchesOutput() {
+		return 0, errors.New("exec: idle timeout or output readiness cannot be used with a detached command")
+	}
+	if 选项.DoubleFork && (c.timeout > 0 || c.pidFilePath != "" || len(c.forward) > 0) {
+		return 0, errors.New("exec: timeout, pidfile or signal forwarding cannot be used with DoubleFork")
+	}
+	c.I设置进程组(ProcessGroup_新会话)
+	// 先展开通配符，DoubleFork会改写参数。
+	if err := c.I展开通配符(); err != nil {
+		return 0, err
+	}
+
+	var opened []*os.File
+	defer func() {
+		for _, f := range opened {
+			f.Close()
+		}
+	}()
+	open := func(name string, flag int) (*os.File, error) {
+		f, err := os.OpenFile(name, flag, 0644)
+		if err == nil {
+			opened = append(opened, f)
+		}
+		return f, err
+	}
+	if c.Cmd父类.Stdin == nil {
+		f, err := open(os.DevNull, os.O_RDONLY)
+		if err != nil {
+			return 0, err
+		}
+		c.Cmd父类.Stdin = f
+	}
+	logs := map[string]*os.File{}
+	for i, name := range []string{选项.Stdout, 选项.Stderr} {
+		target := &c.Cmd父类.Stdout
+		if i == 1 {
+			target = &c.Cmd父类.Stderr
+		}
+		if *target != nil {
+			continue
+		}
+		if name == "" {
+			name = os.DevNull
+		}
+		f := logs[name]
+		if f == nil {
+			var err error
+			if f, err = open(name, os.O_WRONLY|os.O_CREATE|os.O_APPEND); err != nil {
+				return 0, err
+			}
+			logs[name] = f
+		}
+		*target = f
+	}
+
+	var pf *PidFile
+	if 选项.PidFile != "" {
+		var err error
+		if pf, err = I打开PidFile(选项.PidFile); err != nil {
+			return 0, err
+		}
+		c.Cmd父类.ExtraFiles = append(c.Cmd父类.ExtraFiles, pf.f)
+	}
+
+	pid, err := c.startDetached(选项.DoubleFork)
+	if err != nil {
+		pf.I关闭()
+		return 0, err
+	}
+	if pf != nil {
+		err = pf.I写入(pid)
+		pf.release()
+	}
+	return pid, err
+}
+
+// startDetached 启动命令并返回它的进程号，不留下需要调用方等待的子进程。
+func (c *Cmd) startDetached(doubleFork bool) (int, error) {
+	if !doubleFork {
+		if err := c.I运行_异步(); err != nil {
+			return 0, err
+		}
+		go c.I等待运行完成()
+		return c.Cmd父类.Process.Pid, nil
+	}
+
+	// 中间的shell在前台启动命令，命令在exec之前通过一个额外的管道报告自己的进程号，
+	// 然后终止中间进程。不用 & 在后台启动，因为没有作业控制的shell会使后台命令忽略SIGINT和SIGQUIT，
+	// 而且这种忽略不能用trap恢复，会被命令继承。
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		return 0, err
+	}
+	defer pr.Close()
+	fd := strconv.Itoa(3 + len(c.Cmd父类.ExtraFiles))
+	c.Cmd父类.ExtraFiles = append(c.Cmd父类.ExtraFiles, pw)
+	script := `sh -c 'echo $$ >&` + fd + `; exec ` + fd + `>&-; exec "$@"' sh "$@"; :`
+	c.Cmd父类.Args = append([]string{"sh", "-c", script, "sh", c.Cmd父类.Path}, c.Cmd父类.Args[1:]...)
+	c.Cmd父类.Path = "/bin/sh"
+	err = c.I运行_异步()
+	pw.Close()
+	if err != nil {
+		return 0, err
+	}
+	line, rerr := bufio.NewReader(pr).ReadString('\n')
+	pid, perr := strconv.Atoi(strings.TrimSpace(line))
+	if perr == nil {
+		// 只终止中间进程本身，命令与它在同一个进程组中。
+		c.Cmd父类.Process.Kill()
+	}
+	werr := c.I等待运行完成()
+	if perr != nil {
+		if werr != nil {
+			return 0, errors.New("exec: detach: " + werr.Error())
+		}
+		if rerr != nil {
+			return 0, errors.New("exec: detach: " + rerr.Error())
+		}
+		return 0, errors.New("exec: detach: bad pid " + strconv.Quote(line))
+	}
+	return pid, nil
+}
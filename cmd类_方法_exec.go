@@ -3,8 +3,10 @@
 // Package cmd类 Package exec 包exec运行外部命令。它包装os.StartProcess，以使重新映射stdin和stdout、将IO与管道连接以及进行其他调整更加容易。
 //
 // 与来自C和其他语言的“系统”库调用不同，osexec包故意不调用系统shell，也不扩展任何glob模式或处理通常由shell执行的其他扩展、管道或重定向。
-// 该包的行为更像C的“exec”函数家族。要扩展glob模式，可以直接调用shell，小心避开任何危险的输入，或者使用pathfilepath包的glob函数。
-// 要扩展环境变量，请使用包os的ExpandEnv。
+// 该包的行为更像C的“exec”函数家族。要扩展glob模式，可以直接调用shell，小心避开任何危险的输入，或者使用pathfilepath包的glob函数，或用 I通配 标记参数，由命令在启动时展开。
+// 要扩展环境变量，请使用包os的ExpandEnv，或用 I展开环境变量 按命令自己的环境展开参数。
+// 要执行配置文件中类似 "grep -v foo input.txt | sort > out.txt" 的简单命令行，可以使用 I解析命令行，
+// 它只接受无需shell即可安全执行的语法子集，并直接启动各个程序。
 //
 // 请注意，此包中的示例假定为Unix系统。它们可能不会在Windows上运行，也不会在golang.org和godoc.org使用的Go Playground上运行。
 //
@@ -68,12 +70,15 @@
 package cmd类
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"io"
 	"os"
 	"os/exec"
 	"strconv"
+	"sync"
+	"time"
 )
 
 // Error LookPath无法将文件分类为可执行文件时返回。
@@ -95,6 +100,38 @@ func (e *Error) Unwrap() error { return e.Err }
 // Cmd在调用其Run、Output或CombinedOutput方法后无法重用。
 type Cmd struct {
 	Cmd父类 *exec.Cmd
+
+	stdin       *stdinSource     // 由 I设置Stdin_ 系列方法指定的标准输入来源
+	stdinFeeder *stdinFeeder     // 启动后向子进程写入标准输入的协程，等待时收尾
+	stdinPolicy Stdin断管策略        // 写入标准输入遇到断管错误时的处理方式
+	stdinPipe   *stdinPipeWriter // I取Stdin管道 返回的写入端
+	substs      []*procSubst     // 由 I进程替换_ 系列方法添加的进程替换
+	fifoDir     string           // 存放命名管道的私有临时目录
+	fifos       []*namedFifo     // 由 I命名管道_ 系列方法创建的命名管道
+	redirs      []redirect       // 由 I解析命令行 得到的重定向
+	redirFiles  []*os.File       // 为重定向打开的文件，启动后关闭
+	globPolicy  Glob无匹配策略        // I通配 标记的模式没有匹配时的处理方式
+	pgroup      ProcessGroup模式   // 子进程的进程组模式
+	ctx         context.Context  // I设置命令_上下文 的上下文
+	groupGone   bool             // 进程组已经没有成员，受pidMu保护
+	pidMu       sync.Mutex       // 保护pidfd、exited和reaped
+	pidfd       *int             // 启动时取得的pidfd，不可用时为-1
+	ownPidfd    bool             // pidfd由本包创建，回收进程后关闭
+	exited      chan struct{}    // I退出通知 返回的通道
+	reaped      bool             // 进程已退出并即将或已经被回收，受pidMu保护
+	clock       runClock         // 运行状态和不含暂停时间的运行时长
+	timeout     time.Duration    // I设置超时 设置的最长运行时长
+	idleTimeout time.Duration    // I设置无输出超时 设置的时长
+	idleCPU     bool             // 无输出超时是否也检查CPU时间
+	idleCPUTime uint64           // 上次检查时进程树的CPU时间，受clock.mu保护
+	outCopies   []*outputCopy    // 为观察输出而代替 *os.File 的管道
+	ready       *readyState      // I设置就绪条件 设置的条件
+	forward     []os.Signal      // I设置信号转发 设置的信号
+	forwarder   *forwarder       // 命令运行期间转发信号的协程
+	pidFilePath string           // I设置PidFile 设置的路径
+	pidFile     *PidFile         // 启动前取得的pidfile，回收子进程后删除
+	waitMu      sync.Mutex       // 保护waiter
+	waiter      *waiter          // 对进程的唯一一次等待
 }
 
 // I设置命令 返回Cmd结构以使用给定参数执行命名程序。
@@ -110,29 +147,31 @@ type Cmd struct {
 // 命令使用与使用CommandLineToArgvW的应用程序兼容的算法（这是最常见的方法）将Args组合并引用到命令行字符串中.
 // 值得注意的例外是msiexec.exe和cmd.exe（以及所有批处理文件）， 它们具有不同的去激励算法。
 // 在这些或其他类似情况下, 您可以自己引用，并在SysProcAttr.CmdLine中提供完整的命令行，将Args留空。
+// I组合Windows命令行、I转义cmd参数 和 I组合批处理命令行 可以在任何系统上构造这样的命令行。
 func I设置命令(进程名 string, 命令参数 ...string) *Cmd {
 	c := exec.Command(进程名, 命令参数...)
 	if c == nil {
 		return nil
 	}
 	//
-	return &Cmd{c}
+	return &Cmd{Cmd父类: c}
 }
 
 // I设置命令_上下文 与 I设置命令 类似，但包含上下文。
 //
 // 如果上下文在命令自身完成之前完成，则提供的上下文用于终止进程（通过调用os.ProcessKill）。
+// 用 I设置进程组 设置了新进程组时终止整个进程组。
 func I设置命令_上下文(上下文 context.Context, 进程名 string, 命令参数 ...string) *Cmd {
 	c := exec.CommandContext(上下文, 进程名, 命令参数...)
 	if c == nil {
 		return nil
 	}
-	return &Cmd{c}
+	return &Cmd{Cmd父类: c, ctx: 上下文}
 }
 
 // I取命令 返回c的可读描述。
 // 它仅用于调试。
-// 特别是，它不适合用作外壳的输入。
+// 特别是，它不适合用作外壳的输入；需要可以粘贴到shell中的形式时请使用 ShellString。
 // String的输出可能因Go版本而异。
 func (c *Cmd) I取命令() string {
 	if c == nil {
@@ -163,7 +202,10 @@ func (c *Cmd) I运行() error {
 	if c == nil {
 		return errors.New("cmd类对象为nil")
 	}
-	return c.Cmd父类.Run()
+	if err := c.I运行_异步(); err != nil {
+		return err
+	}
+	return c.I等待运行完成()
 } //I运行
 
 // I运行_异步 启动指定的命令，但不等待它完成。
@@ -175,7 +217,19 @@ func (c *Cmd) I运行_异步() error {
 	if c == nil {
 		return errors.New("cmd类对象为nil")
 	}
-	return c.Cmd父类.Start()
+	if err := c.beforeStart(); err != nil {
+		c.startFailed()
+		return err
+	}
+	if err := c.startProcess(); err != nil {
+		c.startFailed()
+		return err
+	}
+	if err := c.startPidFile(); err != nil {
+		return err
+	}
+	c.afterStart()
+	return nil
 }
 
 // ExitError 报告命令退出失败。
@@ -204,12 +258,21 @@ func (e *ExitError) Error() string {
 //
 // 如果c.Stdin、c.Stdout或c.Stderr中的任何一个不是 *os.File, 等待还等待各个IO循环复制到进程或从进程复制完成。
 //
-// I等待运行完成 释放与Cmd关联的任何资源。
+// I等待运行完成 释放与Cmd关联的任何资源。需要限制等待时间或同时等待多个命令时见 I等待运行完成_超时 和 I等待任一。
 func (c *Cmd) I等待运行完成() error {
 	if c == nil {
 		return errors.New("cmd类对象为nil")
 	}
-	return c.Cmd父类.Wait()
+	if c.Cmd父类.Process == nil {
+		return c.afterWait(c.waitProcess())
+	}
+	w, created := c.getWaiter()
+	if created {
+		w.run(c)
+	} else {
+		<-w.done
+	}
+	return c.collect(w)
 }
 
 // I运行_带返回值 运行命令并返回其标准输出。
@@ -219,7 +282,25 @@ func (c *Cmd) I运行_带返回值() ([]byte, error) {
 	if c == nil {
 		return nil, errors.New("cmd类对象为nil")
 	}
-	return c.Cmd父类.Output()
+	if c.Cmd父类.Stdout != nil {
+		return nil, errors.New("exec: Stdout already set")
+	}
+	var stdout bytes.Buffer
+	c.Cmd父类.Stdout = &stdout
+
+	// 观察输出时 Cmd父类.Stderr 会被替换，因此保留saver本身。
+	var saver *prefixSuffixSaver
+	if c.Cmd父类.Stderr == nil {
+		saver = &prefixSuffixSaver{N: 32 << 10}
+		c.Cmd父类.Stderr = saver
+	}
+
+	err := c.I运行()
+	var ee *exec.ExitError
+	if saver != nil && errors.As(err, &ee) {
+		ee.Stderr = saver.Bytes()
+	}
+	return stdout.Bytes(), err
 }
 
 // I运行_带组合返回值 运行该命令并返回其组合的标准输出和标准错误。
@@ -236,16 +317,33 @@ func (c *Cmd) I运行_带组合返回值() ([]byte, error) {
 	if c == nil {
 		return nil, errors.New("cmd类对象为nil")
 	}
-	return c.Cmd父类.CombinedOutput()
+	if c.Cmd父类.Stdout != nil {
+		return nil, errors.New("exec: Stdout already set")
+	}
+	if c.Cmd父类.Stderr != nil {
+		return nil, errors.New("exec: Stderr already set")
+	}
+	var b bytes.Buffer
+	c.Cmd父类.Stdout = &b
+	c.Cmd父类.Stderr = &b
+	err := c.I运行()
+	return b.Bytes(), err
 }
 
 // I取Stdin管道 StdinPipe方法返回一个在命令Start后与命令标准输入关联的管道。Wait方法获知命令结束后会关闭这个管道。
 // 必要时调用者可以调用Close方法来强行关闭管道，例如命令在输入关闭后才会执行返回时需要显式关闭管道。
+//
+// 写入时遇到的断管错误按 I设置Stdin断管策略 设置的策略处理。
 func (c *Cmd) I取Stdin管道() (io.WriteCloser, error) {
 	if c == nil {
 		return nil, errors.New("cmd类对象为nil")
 	}
-	return c.Cmd父类.StdinPipe()
+	wc, err := c.Cmd父类.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	c.stdinPipe = &stdinPipeWriter{WriteCloser: wc, c: c}
+	return c.stdinPipe, nil
 	//Stdin管道
 }
 
@@ -0,0 +1,167 @@
+package cmd类
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// I展开环境变量 展开 Cmd父类.Args 中除程序名以外各参数里的 $VAR 和 ${VAR}，结果直接写回 Cmd父类.Args。
+//
+// 变量取自命令自己的环境（即 I取环境变量数组 的结果：设置了 Cmd父类.Env 时为它，否则为当前进程的环境），
+// 因此应在设置 Cmd父类.Env 之后调用。支持的形式：
+//
+//	$VAR、${VAR}      变量的值
+//	${VAR:-默认值}     VAR未设置或为空时取默认值，${VAR-默认值} 仅在未设置时取默认值
+//	${VAR:?消息}       VAR未设置或为空时返回包含消息的错误，${VAR?消息} 仅在未设置时报错
+//	$$                一个 $
+//
+// 默认值和消息中的变量也会展开。$ 后面不是变量名时原样保留。
+// 严格模式下引用未设置的变量（不带默认值）返回错误；非严格模式下与 os.ExpandEnv 一样展开为空串。
+// 出错时 Cmd父类.Args 不变。
+func (c *Cmd) I展开环境变量(严格模式 bool) error {
+	if c == nil {
+		return errors.New("cmd类对象为nil")
+	}
+	env := make(map[string]string)
+	for _, kv := range c.Cmd父类.Environ() {
+		if name, value, ok := strings.Cut(kv, "="); ok {
+			env[name] = value
+		}
+	}
+	e := &expander{env: env, strict: 严格模式}
+	args := make([]string, len(c.Cmd父类.Args))
+	for i, arg := range c.Cmd父类.Args {
+		if i == 0 {
+			args[i] = arg
+			continue
+		}
+		s, err := e.expand(arg)
+		if err != nil {
+			return errors.New("exec: expand " + strconv.Quote(arg) + ": " + err.Error())
+		}
+		args[i] = s
+	}
+	c.Cmd父类.Args = args
+	return nil
+}
+
+// expander 实现 I展开环境变量 的展开规则。
+type expander struct {
+	env    map[string]string
+	strict bool
+}
+
+func (e *expander) expand(s string) (string, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 >= len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		switch next := s[i+1]; {
+		case next == '$':
+			b.WriteByte('$')
+			i++
+		case next == '{':
+			end := matchingBrace(s, i+2)
+			if end < 0 {
+				return "", errors.New("missing closing brace")
+			}
+			v, err := e.braced(s[i+2 : end])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(v)
+			i = end
+		case isNameStart(next):
+			j := i + 2
+			for j < len(s) && isNameChar(s[j]) {
+				j++
+			}
+			v, err := e.lookup(s[i+1 : j])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(v)
+			i = j - 1
+		default:
+			b.WriteByte('$')
+		}
+	}
+	return b.String(), nil
+}
+
+// braced 展开 ${...} 中的内容expr。
+func (e *expander) braced(expr string) (string, error) {
+	n := 0
+	for n < len(expr) && isNameChar(expr[n]) {
+		n++
+	}
+	name, rest := expr[:n], expr[n:]
+	if !isName(name) {
+		return "", errors.New("bad substitution ${" + expr + "}")
+	}
+	if rest == "" {
+		return e.lookup(name)
+	}
+	colon := strings.HasPrefix(rest, ":")
+	if colon {
+		rest = rest[1:]
+	}
+	if rest == "" || (rest[0] != '-' && rest[0] != '?') {
+		return "", errors.New("bad substitution ${" + expr + "}")
+	}
+	op, word := rest[0], rest[1:]
+	v, ok := e.env[name]
+	if ok && (!colon || v != "") {
+		return v, nil
+	}
+	word, err := e.expand(word)
+	if err != nil {
+		return "", err
+	}
+	if op == '-' {
+		return word, nil
+	}
+	if word == "" {
+		word = "parameter null or not set"
+	}
+	return "", errors.New(name + ": " + word)
+}
+
+func (e *expander) lookup(name string) (string, error) {
+	v, ok := e.env[name]
+	if !ok && e.strict {
+		return "", errors.New(name + ": parameter not set")
+	}
+	return v, nil
+}
+
+// matchingBrace 返回s中从i开始与之前的 ${ 配对的 } 的下标，没有时返回-1。
+func matchingBrace(s string, i int) int {
+	depth := 1
+	for ; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || '0' <= c && c <= '9'
+}
@@ -0,0 +1,159 @@
+package cmd类
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// namedFifo 描述一个由 I命名管道_ 系列方法创建、路径作为参数交给子进程的命名管道。
+type namedFifo struct {
+	path   string
+	output bool // true 表示子进程写入该路径、本进程一端读取；否则子进程读取
+	cmd    *Cmd
+	r      io.Reader
+	w      io.Writer
+
+	opened chan struct{} // 本进程一端打开后关闭
+	errc   chan error
+}
+
+// I命名管道_读取器 在私有临时目录中创建一个命名管道并返回其路径，命令从该路径读到的是 读取器 中的数据。
+//
+// 调用方应把返回值作为参数追加到 Cmd父类.Args 中，对于只接受输入文件路径的程序很有用：
+//
+//	cmd := cmd类.I设置命令("legacy-tool", "-i")
+//	in, err := cmd.I命名管道_读取器(strings.NewReader(data))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	cmd.Cmd父类.Args = append(cmd.Cmd父类.Args, in)
+//
+// 命名管道的本进程一端在命令启动后于单独的协程中打开，因此不论命令以什么顺序打开各个路径都不会死锁；
+// 命令退出时仍未打开的路径由 I等待运行完成 解除阻塞。命令结束或启动失败后临时目录被删除，
+// 从未运行的命令不会自动删除它。复制数据的错误由 I等待运行完成 返回，断管错误按 Stdin断管策略 处理。
+// 仅在支持mkfifo的Unix系统上可用，其他系统返回错误。
+func (c *Cmd) I命名管道_读取器(读取器 io.Reader) (string, error) {
+	return c.addFifo(&namedFifo{r: 读取器})
+}
+
+// I命名管道_写入器 与 I命名管道_读取器 类似，但命令写入返回路径的数据被复制到 写入器。
+func (c *Cmd) I命名管道_写入器(写入器 io.Writer) (string, error) {
+	return c.addFifo(&namedFifo{output: true, w: 写入器})
+}
+
+// I命名管道_输入 与 I命名管道_读取器 类似，但命令从返回路径读到的是 子命令 的标准输出。
+// 子命令 在命名管道的本进程一端打开后才启动，它的退出状态不影响命令的结果。
+func (c *Cmd) I命名管道_输入(子命令 *Cmd) (string, error) {
+	if 子命令.Cmd父类.Stdout != nil {
+		return "", errors.New("exec: Stdout already set on named pipe command")
+	}
+	return c.addFifo(&namedFifo{cmd: 子命令})
+}
+
+// I命名管道_输出 与 I命名管道_写入器 类似，但命令写入返回路径的数据成为 子命令 的标准输入。
+func (c *Cmd) I命名管道_输出(子命令 *Cmd) (string, error) {
+	if 子命令.Cmd父类.Stdin != nil || 子命令.stdin != nil {
+		return "", errors.New("exec: Stdin already set on named pipe command")
+	}
+	return c.addFifo(&namedFifo{output: true, cmd: 子命令})
+}
+
+// addFifo 在c的私有临时目录中为f创建命名管道，必要时先创建该目录。
+func (c *Cmd) addFifo(f *namedFifo) (string, error) {
+	if c.fifoDir == "" {
+		dir, err := os.MkdirTemp("", "cmd类-fifo-")
+		if err != nil {
+			return "", err
+		}
+		c.fifoDir = dir
+	}
+	f.path = filepath.Join(c.fifoDir, "fifo"+strconv.Itoa(len(c.fifos)))
+	if err := mkfifo(f.path); err != nil {
+		return "", err
+	}
+	c.fifos = append(c.fifos, f)
+	return f.path, nil
+}
+
+// abortFifos 在启动失败时删除命名管道所在的临时目录。
+func (c *Cmd) abortFifos() {
+	if c.fifoDir != "" {
+		os.RemoveAll(c.fifoDir)
+		c.fifoDir = ""
+	}
+	c.fifos = nil
+}
+
+// startFifos 在命令启动后为每个命名管道启动一个协程，打开本进程一端并复制数据或启动子命令。
+func (c *Cmd) startFifos() {
+	for _, f := range c.fifos {
+		f.opened = make(chan struct{})
+		f.errc = make(chan error, 1)
+		go func(f *namedFifo) {
+			f.errc <- f.run()
+		}(f)
+	}
+}
+
+func (f *namedFifo) run() error {
+	flag := os.O_WRONLY
+	if f.output {
+		flag = os.O_RDONLY
+	}
+	file, err := os.OpenFile(f.path, flag, 0)
+	close(f.opened)
+	if err != nil {
+		return err
+	}
+	if f.cmd != nil {
+		if f.output {
+			f.cmd.Cmd父类.Stdin = file
+		} else {
+			f.cmd.Cmd父类.Stdout = file
+		}
+		err := f.cmd.I运行_异步()
+		file.Close()
+		if err != nil {
+			return err
+		}
+		f.cmd.I等待运行完成()
+		return nil
+	}
+	if f.output {
+		_, err = io.Copy(f.w, file)
+	} else {
+		_, err = io.Copy(file, f.r)
+	}
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// waitFifos 在命令退出后解除仍阻塞在打开上的协程，等待所有协程结束并删除临时目录，返回第一个错误。
+func (c *Cmd) waitFifos() error {
+	var firstErr error
+	for _, f := range c.fifos {
+		select {
+		case <-f.opened:
+		default:
+			// 命令没有打开该路径就退出了。
+			if partner, err := openFifoPartner(f.path); err == nil {
+				<-f.opened
+				partner.Close()
+			}
+		}
+		err := <-f.errc
+		if err != nil && !f.output && c.stdinPolicy != Stdin断管_总是报告 && isFifoBrokenPipe(err) {
+			err = nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	c.abortFifos()
+	return firstErr
+}
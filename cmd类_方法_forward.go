@@ -0,0 +1,78 @@
+package cmd类
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+)
+
+// I设置信号转发 使命令运行期间当前进程收到的 信号组 中的信号转发给命令，须在启动前调用。
+// 没有指定 信号组 时转发SIGINT、SIGTERM和SIGHUP（在没有这些信号的系统上只转发 os.Interrupt）。
+//
+// 信号通过 I发送信号 转发，设置了新进程组（见 I设置进程组）时发给整个进程组。
+// 第一次收到的信号原样转发；命令结束前再次收到其中任何信号时改用 I终止 强制终止命令。
+// 信号无法发给命令时也直接用 I终止，例如在Windows上不能向其他进程发送 os.Interrupt，
+// 因此在Windows上收到的第一个信号就会终止命令。
+// 转发期间这些信号不再按默认方式终止当前进程；命令退出后取消注册（signal.Stop），恢复原来的处理方式。
+// 在Linux上转发在进程被回收之前停止，其他系统上在回收之后，
+// 此时已退出的命令不再接收信号（I发送信号 返回 os.ErrProcessDone）。
+//
+// 子进程与终端的前台进程组相同时，终端产生的SIGINT本来就会发给它，转发会使它收到两次，
+// 这种情况下应同时使用 I设置进程组。
+func (c *Cmd) I设置信号转发(信号组 ...os.Signal) {
+	if len(信号组) == 0 {
+		信号组 = defaultForwardSignals
+	}
+	c.forward = 信号组
+}
+
+// forwarder 是转发信号的协程的状态。
+type forwarder struct {
+	sigc chan os.Signal
+	stop chan struct{}
+	done chan struct{}
+}
+
+// startForward 在进程启动后开始转发信号。
+func (c *Cmd) startForward() {
+	if len(c.forward) == 0 {
+		return
+	}
+	f := &forwarder{
+		sigc: make(chan os.Signal, len(c.forward)+1),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	signal.Notify(f.sigc, c.forward...)
+	c.forwarder = f
+	go func() {
+		defer close(f.done)
+		received := false
+		for {
+			select {
+			case sig := <-f.sigc:
+				// 进程已经退出时没有可做的事。
+				if received {
+					c.I终止()
+				} else if err := c.I发送信号(sig); err != nil && !errors.Is(err, os.ErrProcessDone) {
+					c.I终止()
+				}
+				received = true
+			case <-f.stop:
+				return
+			}
+		}
+	}()
+}
+
+// stopForward 在进程退出后停止转发并取消信号注册。
+func (c *Cmd) stopForward() {
+	f := c.forwarder
+	if f == nil {
+		return
+	}
+	signal.Stop(f.sigc)
+	close(f.stop)
+	<-f.done
+	c.forwarder = nil
+}
@@ -0,0 +1,236 @@
+package cmd类
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// globMarker 是 I通配 返回值的前缀。参数中不能含有NUL，未经展开的标记不会被误当作普通参数传给子进程。
+const globMarker = "\x00cmd类.glob\x00"
+
+// Glob无匹配策略 决定 I通配 标记的模式没有匹配任何文件时如何处理。
+type Glob无匹配策略 int
+
+const (
+	// Glob无匹配_报错 使展开返回错误，这是默认策略。
+	Glob无匹配_报错 Glob无匹配策略 = iota
+
+	// Glob无匹配_原样 把模式本身作为参数，与未设置nullglob的shell一致。
+	Glob无匹配_原样
+
+	// Glob无匹配_忽略 不产生任何参数，与设置了nullglob的shell一致。
+	Glob无匹配_忽略
+)
+
+// I通配 把 模式 标记为需要展开的glob模式，返回值用作 I设置命令 的参数，例如：
+//
+//	cmd := cmd类.I设置命令("gofmt", "-l", cmd类.I通配("**/*.go"))
+//
+// 本包不会展开普通参数中的glob字符，只有这样标记的参数才会在命令启动时（或调用 I展开通配符 时）
+// 被替换为匹配的路径。模式的语法同 filepath.Match，另外整段为 ** 时匹配零个或多个目录，
+// 位于模式末尾时匹配其下的全部文件和目录（如 "a/**" 匹配a本身和它下面的一切）；
+// 与shell一样，* 等不匹配以 . 开头的名称，除非该段模式本身以 . 开头，** 也不进入隐藏目录和符号链接指向的目录。
+// 相对模式以命令的 Cmd父类.Dir 为基准，结果也是相对于它的路径。每个模式的结果按字典序排列。
+func I通配(模式 string) string {
+	return globMarker + 模式
+}
+
+// I设置通配无匹配策略 设置 I通配 标记的模式没有匹配时的处理方式。
+func (c *Cmd) I设置通配无匹配策略(策略 Glob无匹配策略) {
+	c.globPolicy = 策略
+}
+
+// I展开通配符 立即展开 Cmd父类.Args 中由 I通配 标记的参数。命令启动时会自动调用它，
+// 提前调用可以在启动前检查展开结果或错误。
+//
+// 展开后参数和环境变量的总大小超过系统对新进程的限制（ARG_MAX）时返回错误，Cmd父类.Args 保持不变。
+func (c *Cmd) I展开通配符() error {
+	if c == nil {
+		return errors.New("cmd类对象为nil")
+	}
+	var args []string
+	expanded := false
+	for i, arg := range c.Cmd父类.Args {
+		pattern, ok := strings.CutPrefix(arg, globMarker)
+		if !ok || i == 0 {
+			args = append(args, arg)
+			continue
+		}
+		expanded = true
+		matches, err := globPattern(c.Cmd父类.Dir, pattern)
+		if err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			switch c.globPolicy {
+			case Glob无匹配_原样:
+				matches = []string{pattern}
+			case Glob无匹配_报错:
+				return errors.New("exec: no matches for pattern " + strconv.Quote(pattern))
+			}
+		}
+		args = append(args, matches...)
+	}
+	if !expanded {
+		return nil
+	}
+	if err := checkArgMax(args, c.Cmd父类.Environ()); err != nil {
+		return err
+	}
+	c.Cmd父类.Args = args
+	return nil
+}
+
+// globPattern 返回相对于dir的pattern的全部匹配，按字典序排列。
+func globPattern(dir, pattern string) ([]string, error) {
+	segs := strings.Split(filepath.ToSlash(pattern), "/")
+	for _, seg := range segs {
+		if seg != "**" && hasGlobMeta(seg) {
+			if _, err := filepath.Match(seg, ""); err != nil {
+				return nil, errors.New("exec: bad pattern " + strconv.Quote(pattern) + ": " + err.Error())
+			}
+		}
+	}
+
+	// 不含通配符的前缀按字面处理。
+	k := 0
+	for k < len(segs) && segs[k] != "**" && !hasGlobMeta(segs[k]) {
+		k++
+	}
+	prefix := filepath.FromSlash(strings.Join(segs[:k], "/"))
+	if k > 0 && segs[0] == "" {
+		prefix = string(filepath.Separator) + prefix
+	} else if prefix != "" && filepath.VolumeName(prefix) == prefix && k < len(segs) {
+		prefix += string(filepath.Separator) // C:/* 中的 C: 是卷名而不是相对路径
+	}
+	if k == len(segs) {
+		if _, err := os.Lstat(fsPath(dir, prefix)); err != nil {
+			return nil, nil
+		}
+		return []string{prefix}, nil
+	}
+
+	g := &globber{dir: dir, seen: make(map[string]bool)}
+	g.walk(prefix, segs[k:])
+	sort.Strings(g.matches)
+	return g.matches, nil
+}
+
+type globber struct {
+	dir     string
+	matches []string
+	seen    map[string]bool
+}
+
+// walk 在路径p（相对于g.dir）下匹配剩余的各段模式。
+func (g *globber) walk(p string, segs []string) {
+	if len(segs) == 0 {
+		if p != "" && !g.seen[p] {
+			g.seen[p] = true
+			g.matches = append(g.matches, p)
+		}
+		return
+	}
+	seg := segs[0]
+	if !hasGlobMeta(seg) && seg != "**" {
+		next := filepath.Join(p, seg)
+		if _, err := os.Lstat(fsPath(g.dir, next)); err == nil {
+			g.walk(next, segs[1:])
+		}
+		return
+	}
+	entries, err := os.ReadDir(fsPath(g.dir, p))
+	if seg == "**" {
+		g.walk(p, segs[1:])
+		for _, e := range entries {
+			if strings.HasPrefix(e.Name(), ".") {
+				continue
+			}
+			if e.IsDir() {
+				g.walk(filepath.Join(p, e.Name()), segs)
+			} else if len(segs) == 1 {
+				// 末尾的 ** 与shell的globstar一样也匹配文件。
+				g.walk(filepath.Join(p, e.Name()), nil)
+			}
+		}
+		return
+	}
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasPrefix(name, ".") && !strings.HasPrefix(seg, ".") {
+			continue
+		}
+		if ok, _ := filepath.Match(seg, name); !ok {
+			continue
+		}
+		next := filepath.Join(p, name)
+		if len(segs) > 1 {
+			if fi, err := os.Stat(fsPath(g.dir, next)); err != nil || !fi.IsDir() {
+				continue
+			}
+		}
+		g.walk(next, segs[1:])
+	}
+}
+
+// fsPath 返回相对于dir的路径p在本进程中的路径。
+func fsPath(dir, p string) string {
+	if p == "" {
+		p = "."
+	}
+	if dir == "" || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(dir, p)
+}
+
+func hasGlobMeta(s string) bool {
+	return strings.ContainsAny(s, `*?[\`)
+}
+
+// argMax 返回新进程的参数和环境变量总大小的保守上限。
+func argMax() int {
+	switch runtime.GOOS {
+	case "linux":
+		return 2 << 20
+	case "darwin", "ios", "freebsd":
+		return 256 << 10
+	case "windows":
+		return 32767
+	}
+	return 128 << 10
+}
+
+// checkArgMax 报告args和env能否传给新进程。
+func checkArgMax(args, env []string) error {
+	size := 0
+	if runtime.GOOS == "windows" {
+		// 命令行中每个参数还需要分隔空格和可能的引号，环境块不计入命令行长度。
+		for _, s := range args {
+			size += len(s) + 3
+		}
+	} else {
+		// 每个字符串还需要结尾的NUL和一个指针。
+		for _, list := range [][]string{args, env} {
+			for _, s := range list {
+				// Linux还限制单个字符串的长度（MAX_ARG_STRLEN）。
+				if runtime.GOOS == "linux" && len(s) >= 128<<10 {
+					return errors.New("exec: argument too long after glob expansion")
+				}
+				size += len(s) + 1 + 8
+			}
+		}
+	}
+	if size > argMax() {
+		return errors.New("exec: argument list too long after glob expansion (" + strconv.Itoa(size) + " bytes)")
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package cmd类
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Group模式 决定 Group 中的命令失败时是否终止其余命令。
+type Group模式 int
+
+const (
+	// Group_快速失败 在任一命令失败时取消组的上下文，终止仍在运行的命令并不再启动新的命令。这是默认模式。
+	Group_快速失败 Group模式 = iota
+
+	// Group_收集全部 让所有命令运行完毕，只在外部上下文结束时终止它们。
+	Group_收集全部
+)
+
+// Group 并发运行一组相关的命令，类似 golang.org/x/sync/errgroup，由 I设置命令组 创建。
+// 组内的命令共享组的上下文：上下文结束时仍在运行的命令通过 I终止 终止。
+//
+// 与 Cmd 一样，Group 在 I等待 返回后不能重用。
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	mode   Group模式
+	sem    chan struct{}
+	wg     sync.WaitGroup
+
+	mu   sync.Mutex
+	cmds []*Cmd
+	errs []error
+}
+
+// I设置命令组 返回一个新的 Group 和从 上下文 派生的组上下文。
+// 组上下文在某个命令失败（Group_快速失败 模式下）或 I等待 返回时取消，可以用来创建同组的其他工作。
+func I设置命令组(上下文 context.Context) (*Group, context.Context) {
+	if 上下文 == nil {
+		panic("nil Context")
+	}
+	ctx, cancel := context.WithCancel(上下文)
+	return &Group{ctx: ctx, cancel: cancel}, ctx
+}
+
+// I设置并发数 限制同时运行的命令数，须在第一次 I添加 之前调用。数量 小于等于0表示不限制。
+func (g *Group) I设置并发数(数量 int) {
+	if 数量 <= 0 {
+		g.sem = nil
+		return
+	}
+	g.sem = make(chan struct{}, 数量)
+}
+
+// I设置失败模式 设置组的失败模式，须在第一次 I添加 之前调用。
+func (g *Group) I设置失败模式(模式 Group模式) {
+	g.mode = 模式
+}
+
+// I添加 在后台启动 命令。达到并发数限制时阻塞，直到有命令结束或组上下文结束。
+// 组上下文已经结束时不再启动 命令，它的错误记为上下文的错误。
+func (g *Group) I添加(命令 *Cmd) {
+	g.mu.Lock()
+	i := len(g.cmds)
+	g.cmds = append(g.cmds, 命令)
+	g.errs = append(g.errs, nil)
+	g.mu.Unlock()
+
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		case <-g.ctx.Done():
+			g.setErr(i, g.ctx.Err())
+			return
+		}
+	}
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if g.sem != nil {
+			defer func() { <-g.sem }()
+		}
+		g.setErr(i, g.run(命令))
+	}()
+}
+
+// run 运行一个命令，组上下文结束时终止它。
+func (g *Group) run(c *Cmd) error {
+	if err := g.ctx.Err(); err != nil {
+		return err
+	}
+	h, err := c.I运行_异步句柄()
+	if err != nil {
+		return err
+	}
+	select {
+	case <-h.Done():
+		return h.Result()
+	case <-g.ctx.Done():
+		c.I终止()
+		if err := h.Result(); err == nil {
+			return nil
+		}
+		return g.ctx.Err()
+	}
+}
+
+func (g *Group) setErr(i int, err error) {
+	g.mu.Lock()
+	g.errs[i] = err
+	g.mu.Unlock()
+	if err != nil && g.mode == Group_快速失败 {
+		g.cancel()
+	}
+}
+
+// I等待 等待所有已添加的命令结束，然后取消组上下文。
+// 所有命令都成功时返回nil，否则返回列出每个失败命令的 *GroupError。
+// 快速失败模式下被终止或未启动的命令的错误是组上下文的错误（context.Canceled）。
+func (g *Group) I等待() error {
+	g.wg.Wait()
+	g.cancel()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, err := range g.errs {
+		if err != nil {
+			return &GroupError{Errs: g.errs, cmds: g.cmds}
+		}
+	}
+	return nil
+}
+
+// I取各命令错误 返回各命令的错误，顺序与 I添加 的顺序相同，成功的命令为nil。须在 I等待 返回后调用。
+func (g *Group) I取各命令错误() []error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]error(nil), g.errs...)
+}
+
+// GroupError 报告 Group 中失败的命令。
+type GroupError struct {
+	// Errs 与 I添加 的命令一一对应，成功的命令为nil。
+	Errs []error
+
+	cmds []*Cmd
+}
+
+func (e *GroupError) Error() string {
+	var b strings.Builder
+	n := 0
+	for _, err := range e.Errs {
+		if err != nil {
+			n++
+		}
+	}
+	b.WriteString("exec: ")
+	b.WriteString(strconv.Itoa(n))
+	b.WriteString(" of ")
+	b.WriteString(strconv.Itoa(len(e.Errs)))
+	b.WriteString(" commands failed:")
+	for i, err := range e.Errs {
+		if err == nil {
+			continue
+		}
+		b.WriteString(" [")
+		b.WriteString(strconv.Itoa(i))
+		if i < len(e.cmds) && e.cmds[i] != nil {
+			b.WriteString(" ")
+			b.WriteString(e.cmds[i].Cmd父类.Path)
+		}
+		b.WriteString("] ")
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+// Unwrap 返回各失败命令的错误，使 errors.Is 和 errors.As 可以检查其中任意一个。
+func (e *GroupError) Unwrap() []error {
+	var errs []error
+	for _, err := range e.Errs {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,71 @@
+package cmd类
+
+import "time"
+
+// I设置无输出超时 设置命令的无输出超时，须在启动前调用。标准输出和标准错误都连续 时长
+// 没有写出任何数据时用 I终止 终止命令，I等待运行完成 随后返回 Idle 为true的 *TimeoutError
+// （与 ErrTimeout 匹配）。适合发现不退出也不报错、静默挂起的命令。被 I暂停 暂停的时间不计入。
+// 时长 为0表示不限制。
+//
+// 为了观察输出，本包会在子进程和 Cmd父类.Stdout、Cmd父类.Stderr 之间插入管道并复制数据，
+// 即使它们是 *os.File 或nil（nil时数据被丢弃）；子进程因此不再把它们当作终端或普通文件。
+// 所以无输出超时不能与 I分离运行 一起使用。命令被终止后，仍持有这些管道的后代进程
+// 不会让 I等待运行完成 一直等待，它们随后的输出被丢弃。
+func (c *Cmd) I设置无输出超时(时长 time.Duration) {
+	c.idleTimeout = 时长
+	c.idleCPU = false
+}
+
+// I设置无输出超时_检查CPU 与 I设置无输出超时 类似，但命令及其全部后代进程在这段时间内
+// 消耗了CPU时间时也视为仍在工作，适合长时间计算而不输出的命令。
+// CPU时间从/proc读取，仅Linux支持；其他系统上只检查输出。
+func (c *Cmd) I设置无输出超时_检查CPU(时长 time.Duration) {
+	c.idleTimeout = 时长
+	c.idleCPU = true
+}
+
+// touchIdle 记录命令的一次输出。
+func (c *Cmd) touchIdle() {
+	k := &c.clock
+	k.mu.Lock()
+	k.active = k.elapsed()
+	k.mu.Unlock()
+}
+
+// startIdle 在进程启动后记录CPU时间的基准并启动无输出超时协程。
+func (c *Cmd) startIdle() {
+	if c.idleTimeout <= 0 {
+		return
+	}
+	if c.idleCPU {
+		c.idleCPUTime, _ = procTreeCPU(c.Cmd父类.Process.Pid)
+	}
+	var recheck func()
+	if c.idleCPU {
+		recheck = c.checkIdleCPU
+	}
+	go c.watchRunTime(c.idleDeadline, recheck, func() error {
+		return &TimeoutError{Timeout: c.idleTimeout, Idle: true}
+	})
+}
+
+// idleDeadline 返回无输出超时到期时的运行时长，调用时须持有clock.mu。
+func (c *Cmd) idleDeadline() time.Duration {
+	return c.clock.active + c.idleTimeout
+}
+
+// checkIdleCPU 在无输出超时到期时检查进程树的CPU时间，有增加时视为一次活动。
+// 遍历/proc可能较慢，因此不持有clock.mu读取CPU时间。
+func (c *Cmd) checkIdleCPU() {
+	cpu, ok := procTreeCPU(c.Cmd父类.Process.Pid)
+	if !ok {
+		return
+	}
+	k := &c.clock
+	k.mu.Lock()
+	if cpu != c.idleCPUTime {
+		c.idleCPUTime = cpu
+		k.active = k.elapsed()
+	}
+	k.mu.Unlock()
+}
@@ -0,0 +1,218 @@
+package cmd类
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// Run状态 是 I取运行状态 报告的命令状态。
+type Run状态 int
+
+const (
+	Run_未启动 Run状态 = iota
+	Run_运行中
+	Run_已暂停
+	Run_已退出 // I等待运行完成 已回收进程
+)
+
+// ErrTimeout 与命令因超时被终止时 I等待运行完成 返回的 *TimeoutError 匹配（errors.Is）。
+var ErrTimeout = errors.New("exec: command timed out")
+
+// TimeoutError 是命令因超时被本包终止时 I等待运行完成 返回的错误。
+type TimeoutError struct {
+	Timeout time.Duration
+	Idle    bool  // 因 I设置无输出超时 设置的无输出超时而不是总运行时长超时被终止
+	Err     error // 进程被终止后等待得到的错误，通常是 *exec.ExitError
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Idle {
+		return "exec: command produced no output for " + e.Timeout.String()
+	}
+	return "exec: command timed out after " + e.Timeout.String()
+}
+
+func (e *TimeoutError) Unwrap() error { return e.Err }
+
+func (e *TimeoutError) Is(target error) bool { return target == ErrTimeout }
+
+// runClock 记录命令的状态和不含暂停时间的运行时长。
+type runClock struct {
+	mu       sync.Mutex
+	state    Run状态
+	start    time.Time
+	end      time.Time     // 暂停或退出的时刻
+	paused   time.Duration // 已结束的各次暂停的总时长
+	changed  chan struct{} // 每次暂停、恢复或退出时关闭并替换，使等待运行时长的协程重新计算
+	timedOut error         // 超时终止时的 *TimeoutError
+	active   time.Duration // 最后一次输出（或CPU进展）时的运行时长，用于无输出超时
+}
+
+// elapsed 返回不含暂停时间的运行时长，调用时须持有mu。
+func (k *runClock) elapsed() time.Duration {
+	switch k.state {
+	case Run_未启动:
+		return 0
+	case Run_运行中:
+		return time.Since(k.start) - k.paused
+	}
+	return k.end.Sub(k.start) - k.paused
+}
+
+// setState 转换到state并通知等待者，调用时须持有mu。
+func (k *runClock) setState(state Run状态) {
+	now := time.Now()
+	switch {
+	case state == Run_运行中 && k.state == Run_未启动:
+		k.start = now
+	case state == Run_运行中 && k.state == Run_已暂停:
+		k.paused += now.Sub(k.end)
+	case state == Run_已退出 && k.state == Run_已暂停:
+		// 暂停到退出的时间不计入运行时长。
+	default:
+		k.end = now
+	}
+	k.state = state
+	if k.changed != nil {
+		close(k.changed)
+	}
+	k.changed = make(chan struct{})
+}
+
+// I暂停 用SIGSTOP暂停运行中的命令，设置了新进程组（见 I设置进程组）时暂停整个进程组。
+// 暂停期间不计入 I取运行时长，也不计入 I设置超时 和无输出超时。
+// 暂停的进程不处理除SIGKILL以外的信号，直到 I恢复。仅Unix系统支持。
+func (c *Cmd) I暂停() error {
+	return c.pauseResume(Run_运行中, Run_已暂停, stopSignal)
+}
+
+// I恢复 用SIGCONT恢复被 I暂停 暂停的命令。
+func (c *Cmd) I恢复() error {
+	return c.pauseResume(Run_已暂停, Run_运行中, contSignal)
+}
+
+func (c *Cmd) pauseResume(from, to Run状态, sig os.Signal) error {
+	if c == nil {
+		return errors.New("cmd类对象为nil")
+	}
+	if sig == nil {
+		return errPauseUnsupported
+	}
+	k := &c.clock
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.state != from {
+		if from == Run_运行中 {
+			return errors.New("exec: command is not running")
+		}
+		return errors.New("exec: command is not paused")
+	}
+	if err := c.I发送信号(sig); err != nil {
+		return err
+	}
+	k.setState(to)
+	return nil
+}
+
+// I取运行状态 返回命令的当前状态。本包不知道其他途径发送的SIGSTOP和SIGCONT，
+// 进程自行退出后到 I等待运行完成 回收之前仍报告为运行中或已暂停。
+func (c *Cmd) I取运行状态() Run状态 {
+	if c == nil {
+		return Run_未启动
+	}
+	c.clock.mu.Lock()
+	defer c.clock.mu.Unlock()
+	return c.clock.state
+}
+
+// I取运行时长 返回命令从启动到现在（已回收时到回收为止）除去暂停时间的运行时长。
+func (c *Cmd) I取运行时长() time.Duration {
+	if c == nil {
+		return 0
+	}
+	c.clock.mu.Lock()
+	defer c.clock.mu.Unlock()
+	return c.clock.elapsed()
+}
+
+// I设置超时 设置命令的最长运行时长，须在启动前调用。运行时长超过 时长 时用 I终止 终止命令，
+// I等待运行完成 随后返回 *TimeoutError（与 ErrTimeout 匹配）。与 context.WithTimeout 不同，
+// 被 I暂停 暂停的时间不计入运行时长。时长 为0表示不限制。
+func (c *Cmd) I设置超时(时长 time.Duration) {
+	c.timeout = 时长
+}
+
+// startClock 在进程启动后开始计时，并按需启动超时协程。
+func (c *Cmd) startClock() {
+	c.clock.mu.Lock()
+	c.clock.setState(Run_运行中)
+	c.clock.mu.Unlock()
+	if c.timeout > 0 {
+		go c.watchRunTime(func() time.Duration { return c.timeout }, nil, func() error {
+			return &TimeoutError{Timeout: c.timeout}
+		})
+	}
+	c.startIdle()
+}
+
+// stopClock 在进程被回收后停止计时，命令被超时终止时把err包装为超时错误。
+func (c *Cmd) stopClock(err error) error {
+	k := &c.clock
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.state == Run_运行中 || k.state == Run_已暂停 {
+		k.setState(Run_已退出)
+	}
+	if te, ok := k.timedOut.(*TimeoutError); ok && err != nil {
+		te.Err = err
+		return te
+	}
+	return err
+}
+
+// watchRunTime 在不含暂停时间的运行时长达到 deadline 返回的时长时终止命令，并记录 newErr 返回的超时错误。
+// deadline 在持有clock.mu时调用，每次唤醒都重新计算，因此可以随命令的活动推后。
+// recheck 不为nil时，到期后先在不持有clock.mu的情况下调用它一次，让它有机会记录活动，然后重新计算。
+func (c *Cmd) watchRunTime(deadline func() time.Duration, recheck func(), newErr func() error) {
+	k := &c.clock
+	rechecked := false
+	for {
+		k.mu.Lock()
+		state, changed := k.state, k.changed
+		left := time.Duration(0)
+		if state == Run_运行中 {
+			left = deadline() - k.elapsed()
+		}
+		if state == Run_运行中 && left <= 0 && recheck != nil && !rechecked {
+			k.mu.Unlock()
+			recheck()
+			rechecked = true
+			continue
+		}
+		rechecked = false
+		if state == Run_运行中 && left <= 0 {
+			// 持有锁终止，使 I等待运行完成 在记录超时之后才能检查它。先到的超时有效。
+			if c.I终止() == nil && k.timedOut == nil {
+				k.timedOut = newErr()
+			}
+			k.mu.Unlock()
+			return
+		}
+		k.mu.Unlock()
+		switch state {
+		case Run_已退出:
+			return
+		case Run_已暂停:
+			<-changed
+			continue
+		}
+		t := time.NewTimer(left)
+		select {
+		case <-t.C:
+		case <-changed:
+			t.Stop()
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package cmd类
+
+import (
+	"errors"
+	"os"
+)
+
+// ProcessGroup模式 决定子进程是否放入自己的进程组或会话。
+type ProcessGroup模式 int
+
+const (
+	// ProcessGroup_继承 使子进程留在当前进程的进程组中，这是默认模式。
+	ProcessGroup_继承 ProcessGroup模式 = iota
+
+	// ProcessGroup_新进程组 使子进程成为一个新进程组的组长（setpgid）。
+	ProcessGroup_新进程组
+
+	// ProcessGroup_新会话 使子进程成为一个新会话和新进程组的首进程（setsid），同时脱离控制终端。
+	ProcessGroup_新会话
+)
+
+// I设置进程组 设置子进程的进程组模式，须在启动前调用。启动时 Cmd父类.SysProcAttr 被替换为设置了对应字段的副本。
+//
+// 使用新进程组或新会话时，I发送信号 和 I终止 作用于整个进程组，由 I设置命令_上下文 创建的命令
+// 在上下文结束（包括 context.WithTimeout 超时）时也终止整个进程组，而不只是子进程本身，
+// 这样子进程启动的后代进程不会残留下来并继续占用输出管道；子进程本身已经退出、
+// I等待运行完成 仍在等待这些后代进程关闭输出时，上下文结束也会终止它们。
+// 调用 setsid 的后代进程会离开进程组，不受影响。
+//
+// 仅Unix系统支持，在其他系统上使用非默认模式时启动返回错误。
+func (c *Cmd) I设置进程组(模式 ProcessGroup模式) {
+	c.pgroup = 模式
+}
+
+// I发送信号 向已启动的命令发送 信号。设置了新进程组或新会话时信号发给整个进程组。
+// 进程（组）已经不存在时返回 os.ErrProcessDone。
+// 设置了新进程组时，命令本身退出后仍向组中剩下的进程发送信号，直到进程组为空：
+// 进程组还有成员时组号不会被重用，发现进程组为空后不再发送。
+// 只发给命令本身且有pidfd时（见 I取PidFD）通过pidfd发送，不受进程号重用的影响。
+func (c *Cmd) I发送信号(信号 os.Signal) error {
+	if c == nil {
+		return errors.New("cmd类对象为nil")
+	}
+	p := c.Cmd父类.Process
+	if p == nil {
+		return errors.New("exec: not started")
+	}
+	if c.pgroup != ProcessGroup_继承 {
+		c.pidMu.Lock()
+		defer c.pidMu.Unlock()
+		if c.groupGone {
+			return os.ErrProcessDone
+		}
+		err := signalGroup(p.Pid, 信号)
+		if err == os.ErrProcessDone {
+			c.groupGone = true
+		}
+		return err
+	}
+	return c.signalProcess(信号)
+}
+
+// I终止 立即终止已启动的命令（SIGKILL），设置了新进程组或新会话时终止整个进程组。
+// 与 I发送信号 一样，进程（组）已经不存在时返回 os.ErrProcessDone。
+func (c *Cmd) I终止() error {
+	if c == nil {
+		return errors.New("cmd类对象为nil")
+	}
+	return c.I发送信号(os.Kill)
+}
+
+// prepareProcessGroup 把进程组模式落实到 Cmd父类 中，并让上下文结束时终止整个进程组。
+func (c *Cmd) prepareProcessGroup() error {
+	if c.pgroup == ProcessGroup_继承 {
+		return nil
+	}
+	if err := setProcessGroup(c.Cmd父类, c.pgroup); err != nil {
+		return err
+	}
+	// 只有 exec.CommandContext 创建的命令才能设置Cancel。
+	if c.Cmd父类.Cancel != nil {
+		c.Cmd父类.Cancel = c.I终止
+	}
+	return nil
+}
+
+// watchGroupContext 在等待期间上下文结束时终止整个进程组，返回的函数停止监视。
+// Cmd父类 只在命令本身退出之前响应上下文，而留在组中的后代进程可能仍持有输出管道，使等待无法结束。
+func (c *Cmd) watchGroupContext() (stop func()) {
+	if c.pgroup == ProcessGroup_继承 || c.ctx == nil || c.Cmd父类.Process == nil {
+		return func() {}
+	}
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		select {
+		case <-c.ctx.Done():
+			c.I终止()
+		case <-done:
+		}
+	}()
+	return func() {
+		close(done)
+		<-exited
+	}
+}
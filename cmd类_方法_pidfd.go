@@ -0,0 +1,103 @@
+package cmd类
+
+import "os"
+
+// I取PidFD 返回已启动命令的pidfd（Linux的进程文件描述符）和它是否可用。
+//
+// 在Linux上启动时通过CLONE_PIDFD获取pidfd，内核不支持时退回pidfd_open，两者都不可用（Linux 5.3以前）
+// 或在其他系统上时返回 -1, false。pidfd始终指向这个子进程，不受进程号重用的影响，
+// 可以交给epoll等事件循环，进程退出时变为可读。
+// 它归 Cmd 所有，在 I等待运行完成 返回时关闭，调用方不要关闭它；需要在此之后使用时请自行dup。
+//
+// pidfd可用时，I发送信号 和 I终止 （未设置新进程组时）也通过它发送信号。
+func (c *Cmd) I取PidFD() (int, bool) {
+	if c == nil {
+		return -1, false
+	}
+	c.pidMu.Lock()
+	defer c.pidMu.Unlock()
+	if c.pidfd == nil || *c.pidfd < 0 {
+		return -1, false
+	}
+	return *c.pidfd, true
+}
+
+// I退出通知 返回一个通道，命令的进程退出时关闭。命令尚未启动时返回nil。
+//
+// 通道在进程退出后立即关闭，不回收进程，因此仍须调用 I等待运行完成 取得退出状态。
+// 在Linux上它通过轮询pidfd实现（不占用线程），没有pidfd时使用waitid的WNOWAIT；
+// 在其他系统上通道在 I等待运行完成 回收进程后关闭。多次调用返回同一个通道。
+func (c *Cmd) I退出通知() <-chan struct{} {
+	if c == nil || c.Cmd父类.Process == nil {
+		return nil
+	}
+	c.pidMu.Lock()
+	defer c.pidMu.Unlock()
+	if c.exited == nil {
+		c.exited = make(chan struct{})
+		if c.reaped {
+			close(c.exited)
+		} else {
+			fd := -1
+			if c.pidfd != nil {
+				fd = *c.pidfd
+			}
+			watchExit(c.Cmd父类.Process.Pid, fd, c.exited)
+		}
+	}
+	return c.exited
+}
+
+// isReaped 报告进程是否已被回收。后台等待时不能直接读取 Cmd父类.ProcessState。
+func (c *Cmd) isReaped() bool {
+	c.pidMu.Lock()
+	defer c.pidMu.Unlock()
+	return c.reaped
+}
+
+// signalProcess 向命令的进程发送信号，pidfd可用时通过它发送。
+func (c *Cmd) signalProcess(sig os.Signal) error {
+	c.pidMu.Lock()
+	defer c.pidMu.Unlock()
+	if c.reaped {
+		return os.ErrProcessDone
+	}
+	if c.pidfd != nil && *c.pidfd >= 0 {
+		return signalPidfd(*c.pidfd, sig)
+	}
+	return c.Cmd父类.Process.Signal(sig)
+}
+
+// awaitExit 在回收进程之前等待它退出，并把它记录为已回收，此后不再向它或它的进程组发送信号。
+// 只有不回收进程也能等待它退出时（watchesExit）才这样做并返回true，否则由 closePidfd 在回收后记录。
+// 命令没有启动时什么也不做，由 Cmd父类.Wait 报告错误。
+func (c *Cmd) awaitExit() bool {
+	if !watchesExit || c.Cmd父类.Process == nil {
+		return false
+	}
+	waitUnreaped(c.Cmd父类.Process.Pid)
+	c.pidMu.Lock()
+	c.reaped = true
+	c.pidMu.Unlock()
+	return true
+}
+
+// closePidfd 在进程被回收后关闭pidfd，记录进程已被回收，并确保退出通知的通道已关闭。
+func (c *Cmd) closePidfd() {
+	c.pidMu.Lock()
+	defer c.pidMu.Unlock()
+	c.reaped = true
+	if c.pidfd != nil && *c.pidfd >= 0 && c.ownPidfd {
+		closeFd(*c.pidfd)
+		*c.pidfd = -1
+	}
+	if c.exited != nil {
+		select {
+		case <-c.exited:
+		default:
+			if !watchesExit {
+				close(c.exited)
+			}
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package cmd类
+
+import (
+	"errors"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// PidFile 是一个用flock独占的pidfile，由 I打开PidFile 创建。
+//
+// 文件内容为一行 "PID STARTTIME"，STARTTIME是Linux上/proc/PID/stat中的进程启动时间，
+// 用来识别进程号被重用的情况；在其他系统上只有PID。
+type PidFile struct {
+	path string
+	f    *os.File
+}
+
+// PidFileError 是pidfile被一个运行中的进程持有时返回的错误。
+type PidFileError struct {
+	Path string
+	Pid  int // 持有者的进程号，未知时为0
+}
+
+func (e *PidFileError) Error() string {
+	s := "exec: pidfile " + e.Path + " is held by a running process"
+	if e.Pid > 0 {
+		s += " " + strconv.Itoa(e.Pid)
+	}
+	return s
+}
+
+// I打开PidFile 打开（必要时创建）路径为 路径 的pidfile并取得独占锁。
+//
+// 锁被其他进程持有，或者文件中记录的进程仍在运行（进程号和启动时间都吻合）时返回 *PidFileError。
+// 记录的进程已经退出的文件是过期的，会被接管。取得锁后调用 I写入 写入进程号，用 I关闭 删除文件并释放锁。
+// 仅支持flock的Unix系统（Linux、macOS和BSD）。
+func I打开PidFile(路径 string) (*PidFile, error) {
+	if !haveFlock {
+		return nil, errors.New("exec: pidfiles are not supported on " + runtime.GOOS)
+	}
+	for {
+		f, err := os.OpenFile(路径, os.O_RDWR|os.O_CREATE, 0644)
+		if err != nil {
+			return nil, err
+		}
+		if err := lockFile(f); err != nil {
+			f.Close()
+			if err == errLocked {
+				pid, _, _ := I读取PidFile(路径)
+				return nil, &PidFileError{Path: 路径, Pid: pid}
+			}
+			return nil, err
+		}
+		// 上一个持有者可能在我们打开之后、加锁之前删除了文件，这时锁住的是已删除的文件，需要重试。
+		fi, err1 := f.Stat()
+		cur, err2 := os.Stat(路径)
+		if err1 != nil || err2 != nil || !os.SameFile(fi, cur) {
+			f.Close()
+			continue
+		}
+		if pid, alive, _ := readPidFile(f); alive {
+			f.Close()
+			return nil, &PidFileError{Path: 路径, Pid: pid}
+		}
+		return &PidFile{path: 路径, f: f}, nil
+	}
+}
+
+// I写入 把 进程号 （以及它的启动时间）写入pidfile，替换原有内容。
+func (p *PidFile) I写入(进程号 int) error {
+	if p == nil || p.f == nil {
+		return errors.New("exec: pidfile not open")
+	}
+	s := strconv.Itoa(进程号)
+	if start, ok := procStartTime(进程号); ok {
+		s += " " + strconv.FormatUint(start, 10)
+	}
+	if err := p.f.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := p.f.WriteAt([]byte(s+"\n"), 0); err != nil {
+		return err
+	}
+	return p.f.Sync()
+}
+
+// I关闭 删除pidfile并释放锁。
+func (p *PidFile) I关闭() error {
+	if p == nil || p.f == nil {
+		return nil
+	}
+	// 先删除再解锁，等待锁的进程随后会发现文件已被删除。
+	err := os.Remove(p.path)
+	if cerr := p.release(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// release 只释放本进程持有的文件描述符，不删除文件。继承了描述符的子进程仍持有锁。
+func (p *PidFile) release() error {
+	f := p.f
+	p.f = nil
+	return f.Close()
+}
+
+// I读取PidFile 读取 路径 中记录的进程号，并报告该进程是否仍在运行。
+// 在Linux上还核对记录的启动时间，进程号已被其他进程重用时报告为不在运行。
+func I读取PidFile(路径 string) (进程号 int, 运行中 bool, 错误 error) {
+	f, err := os.Open(路径)
+	if err != nil {
+		return 0, false, err
+	}
+	defer f.Close()
+	return readPidFile(f)
+}
+
+func readPidFile(f *os.File) (int, bool, error) {
+	b, err := io.ReadAll(io.NewSectionReader(f, 0, 64))
+	if err != nil {
+		return 0, false, err
+	}
+	fields := strings.Fields(string(b))
+	if len(fields) == 0 {
+		return 0, false, nil
+	}
+	pid, err := strconv.Atoi(fields[0])
+	if err != nil || pid <= 0 {
+		return 0, false, errors.New("exec: malformed pidfile")
+	}
+	if !haveProcStat {
+		return pid, processAlive(pid), nil
+	}
+	start, ok := procStartTime(pid)
+	if !ok {
+		return pid, false, nil
+	}
+	if len(fields) > 1 {
+		if want, err := strconv.ParseUint(fields[1], 10, 64); err != nil || start != want {
+			return pid, false, nil
+		}
+	}
+	return pid, true, nil
+}
+
+// I设置PidFile 使命令启动前用 I打开PidFile 取得 路径 上的pidfile（已有运行中的实例时启动失败），
+// 启动后写入子进程的进程号，I等待运行完成 回收子进程后删除它。
+// 写入失败时 I运行_异步 终止并回收子进程，删除pidfile，返回该错误。
+func (c *Cmd) I设置PidFile(路径 string) {
+	c.pidFilePath = 路径
+}
+
+// preparePidFile 在启动前取得pidfile的锁。
+func (c *Cmd) preparePidFile() error {
+	if c.pidFilePath == "" {
+		return nil
+	}
+	pf, err := I打开PidFile(c.pidFilePath)
+	if err != nil {
+		return err
+	}
+	c.pidFile = pf
+	return nil
+}
+
+// startPidFile 在启动后、afterStart 之前写入子进程的进程号。
+// 写入失败时终止并回收子进程，释放启动前分配的资源，返回写入的错误。
+func (c *Cmd) startPidFile() error {
+	if c.pidFile == nil {
+		return nil
+	}
+	err := c.pidFile.I写入(c.Cmd父类.Process.Pid)
+	if err != nil {
+		c.I终止()
+		c.waitProcess()
+		c.closePidfd()
+		c.startFailed()
+	}
+	return err
+}
+
+// closePidFile 在启动失败或子进程被回收后删除pidfile。
+func (c *Cmd) closePidFile() {
+	if c.pidFile != nil {
+		c.pidFile.I关闭()
+		c.pidFile = nil
+	}
+}
@@ -0,0 +1,257 @@
+package cmd类
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Pipefail模式 决定 Pipeline 如何由各段的结果得出整体的错误。
+type Pipefail模式 int
+
+const (
+	// Pipefail_末段 只看最后一段的结果，与不带pipefail选项的shell一致。这是默认模式。
+	Pipefail_末段 Pipefail模式 = iota
+
+	// Pipefail_首个失败 返回按管道顺序第一个失败段的错误。
+	Pipefail_首个失败
+
+	// Pipefail_全部 任何一段失败时返回列出所有失败段的 *PipelineError。
+	Pipefail_全部
+)
+
+// Pipeline 表示用操作系统管道首尾相连的一组命令，相当于shell中的 a | b | c。
+//
+// 前一段的标准输出直接作为后一段的标准输入，数据不经过本进程复制。
+// 第一段的标准输入和最后一段的标准输出、以及各段的标准错误可以照常设置。
+//
+// 与 Cmd 一样，Pipeline 在运行后不能重用。
+type Pipeline struct {
+	cmds []*Cmd
+	mode Pipefail模式
+	ctx  context.Context
+
+	pipes    []*os.File // 父进程持有的管道两端，全部启动后关闭
+	stopCtx  chan struct{}
+	ctxDone  chan bool // 上下文监视协程结束时发送是否因上下文结束而终止了各段
+	canceled bool      // 各段是否因上下文结束而被终止
+	errs     []error
+	waitOnce sync.Once
+}
+
+// I设置管道 返回依次连接 命令组 的 Pipeline。
+func I设置管道(命令组 ...*Cmd) *Pipeline {
+	return &Pipeline{cmds: 命令组}
+}
+
+// I设置管道_上下文 与 I设置管道 类似，但上下文在管道结束前完成时终止所有段（通过调用各段的 I终止）。
+func I设置管道_上下文(上下文 context.Context, 命令组 ...*Cmd) *Pipeline {
+	if 上下文 == nil {
+		panic("nil Context")
+	}
+	return &Pipeline{cmds: 命令组, ctx: 上下文}
+}
+
+// I设置失败模式 设置由各段结果得出整体错误的方式，须在启动前调用。
+func (p *Pipeline) I设置失败模式(模式 Pipefail模式) {
+	p.mode = 模式
+}
+
+// I取命令组 返回管道的各段。
+func (p *Pipeline) I取命令组() []*Cmd {
+	return p.cmds
+}
+
+// I运行 启动管道的所有段并等待它们全部完成，返回的错误由 Pipefail模式 决定。
+func (p *Pipeline) I运行() error {
+	if err := p.I运行_异步(); err != nil {
+		return err
+	}
+	return p.I等待运行完成()
+}
+
+// I运行_异步 连接并启动管道的所有段，但不等待它们完成。
+//
+// 任何一段启动失败时，已启动的段会被终止并等待，然后返回该错误。
+// 成功调用后必须调用 I等待运行完成 以释放相关的系统资源。
+func (p *Pipeline) I运行_异步() error {
+	if len(p.cmds) == 0 {
+		return errors.New("exec: empty pipeline")
+	}
+	if p.ctx != nil {
+		if err := p.ctx.Err(); err != nil {
+			return err
+		}
+	}
+	if err := p.connect(); err != nil {
+		p.closePipes()
+		return err
+	}
+	for i, c := range p.cmds {
+		if err := c.I运行_异步(); err != nil {
+			p.closePipes()
+			for _, started := range p.cmds[:i] {
+				started.I终止()
+				started.I等待运行完成()
+			}
+			return err
+		}
+	}
+	p.closePipes()
+	if p.ctx != nil {
+		p.stopCtx = make(chan struct{})
+		p.ctxDone = make(chan bool, 1)
+		go p.watchCtx()
+	}
+	return nil
+}
+
+// I等待运行完成 等待管道的所有段退出，返回的错误由 Pipefail模式 决定。
+//
+// 上下文在管道结束前完成时返回上下文的错误。各段自身的结果可由 I取各段错误 获得。
+func (p *Pipeline) I等待运行完成() error {
+	p.waitOnce.Do(func() {
+		p.errs = make([]error, len(p.cmds))
+		for i, c := range p.cmds {
+			p.errs[i] = c.I等待运行完成()
+		}
+		if p.stopCtx != nil {
+			close(p.stopCtx)
+			p.canceled = <-p.ctxDone
+		}
+	})
+	if p.canceled {
+		for _, err := range p.errs {
+			if err != nil {
+				return p.ctx.Err()
+			}
+		}
+	}
+	return p.err()
+}
+
+// I取各段错误 返回 I等待运行完成 得到的各段错误，与 I取命令组 一一对应，成功的段为nil。
+// 在管道结束前调用时返回nil。
+func (p *Pipeline) I取各段错误() []error {
+	return p.errs
+}
+
+// I取各段状态 返回各段的退出状态，与 I取命令组 一一对应，未启动或未等待的段为nil。
+func (p *Pipeline) I取各段状态() []*os.ProcessState {
+	states := make([]*os.ProcessState, len(p.cmds))
+	for i, c := range p.cmds {
+		states[i] = c.Cmd父类.ProcessState
+	}
+	return states
+}
+
+// connect 在相邻的段之间创建管道。失败时撤销已连接的段的 Stdin 和 Stdout。
+func (p *Pipeline) connect() (err error) {
+	defer func() {
+		if err != nil {
+			for i := 0; i < len(p.pipes)/2; i++ {
+				p.cmds[i].Cmd父类.Stdout = nil
+				p.cmds[i+1].Cmd父类.Stdin = nil
+			}
+		}
+	}()
+	for i := 0; i < len(p.cmds)-1; i++ {
+		w, r := p.cmds[i], p.cmds[i+1]
+		if w.Cmd父类.Stdout != nil {
+			return errors.New("exec: Stdout already set on pipeline stage " + strconv.Itoa(i))
+		}
+		if r.Cmd父类.Stdin != nil || r.stdin != nil {
+			return errors.New("exec: Stdin already set on pipeline stage " + strconv.Itoa(i+1))
+		}
+		pr, pw, err := os.Pipe()
+		if err != nil {
+			return err
+		}
+		p.pipes = append(p.pipes, pr, pw)
+		w.Cmd父类.Stdout = pw
+		r.Cmd父类.Stdin = pr
+	}
+	return nil
+}
+
+func (p *Pipeline) closePipes() {
+	for _, f := range p.pipes {
+		f.Close()
+	}
+	p.pipes = nil
+}
+
+// watchCtx 在上下文结束时终止所有段。
+func (p *Pipeline) watchCtx() {
+	select {
+	case <-p.ctx.Done():
+		for _, c := range p.cmds {
+			c.I终止()
+		}
+		p.ctxDone <- true
+	case <-p.stopCtx:
+		p.ctxDone <- false
+	}
+}
+
+// err 按 Pipefail模式 由各段错误得出整体错误。
+func (p *Pipeline) err() error {
+	switch p.mode {
+	case Pipefail_首个失败:
+		for _, err := range p.errs {
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case Pipefail_全部:
+		for _, err := range p.errs {
+			if err != nil {
+				return &PipelineError{Errs: p.errs, cmds: p.cmds}
+			}
+		}
+		return nil
+	}
+	return p.errs[len(p.errs)-1]
+}
+
+// PipelineError 在 Pipefail_全部 模式下报告管道中失败的各段。
+type PipelineError struct {
+	// Errs 与管道的各段一一对应，成功的段为nil。
+	Errs []error
+
+	cmds []*Cmd
+}
+
+func (e *PipelineError) Error() string {
+	var b strings.Builder
+	b.WriteString("exec: pipeline failed:")
+	for i, err := range e.Errs {
+		if err == nil {
+			continue
+		}
+		b.WriteString(" [")
+		b.WriteString(strconv.Itoa(i))
+		if i < len(e.cmds) && e.cmds[i] != nil {
+			b.WriteString(" ")
+			b.WriteString(e.cmds[i].Cmd父类.Path)
+		}
+		b.WriteString("] ")
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+// Unwrap 返回各失败段的错误，使 errors.Is 和 errors.As 可以检查其中任意一个。
+func (e *PipelineError) Unwrap() []error {
+	var errs []error
+	for _, err := range e.Errs {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,159 @@
+package cmd类
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strconv"
+)
+
+// procSubst 描述一个进程替换：一个通过 Cmd父类.ExtraFiles 交给子进程、在参数中以 /dev/fd/N 出现的管道。
+type procSubst struct {
+	index  int  // 在 Cmd父类.ExtraFiles 中预留的位置
+	output bool // true 表示 >(...)，子进程写入；否则为 <(...)，子进程读取
+	cmd    *Cmd
+	r      io.Reader
+	w      io.Writer
+
+	child *os.File // 交给子进程的一端，启动后在父进程中关闭
+	other *os.File // 交给 cmd 或复制协程的一端
+	errc  chan error
+}
+
+// I进程替换_输入 相当于shell中的 <(子命令)：返回一个形如 /dev/fd/N 的路径，
+// 命令启动时 子命令 随之启动，命令从该路径读到的即是 子命令 的标准输出。
+//
+// 调用方应把返回值作为参数追加到 Cmd父类.Args 中，例如：
+//
+//	cmd := cmd类.I设置命令("diff")
+//	a := cmd.I进程替换_输入(cmd类.I设置命令("sort", "a.txt"))
+//	b := cmd.I进程替换_输入(cmd类.I设置命令("sort", "b.txt"))
+//	cmd.Cmd父类.Args = append(cmd.Cmd父类.Args, a, b)
+//
+// 管道占用 Cmd父类.ExtraFiles 中的一个位置，调用后不应再改动 ExtraFiles 中已有的元素。
+// 与shell一样，子命令 的退出状态不影响命令的结果；需要时可在 I等待运行完成 返回后检查 子命令。
+// 进程替换依赖 ExtraFiles 和 /dev/fd，仅在Unix系统上可用。
+func (c *Cmd) I进程替换_输入(子命令 *Cmd) string {
+	return c.addSubst(&procSubst{cmd: 子命令})
+}
+
+// I进程替换_输入读取器 与 I进程替换_输入 类似，但命令从返回的路径读到的是 读取器 中的数据。
+// 复制数据的错误按 Stdin断管策略 处理后由 I等待运行完成 返回。
+func (c *Cmd) I进程替换_输入读取器(读取器 io.Reader) string {
+	return c.addSubst(&procSubst{r: 读取器})
+}
+
+// I进程替换_输出 相当于shell中的 >(子命令)：返回一个形如 /dev/fd/N 的路径，
+// 命令写入该路径的数据成为 子命令 的标准输入。命令退出后 子命令 读到EOF，I等待运行完成 会等待它退出。
+func (c *Cmd) I进程替换_输出(子命令 *Cmd) string {
+	return c.addSubst(&procSubst{output: true, cmd: 子命令})
+}
+
+// I进程替换_输出写入器 与 I进程替换_输出 类似，但命令写入返回路径的数据被复制到 写入器。
+// I等待运行完成 会等待复制完成，并返回复制时的错误。
+func (c *Cmd) I进程替换_输出写入器(写入器 io.Writer) string {
+	return c.addSubst(&procSubst{output: true, w: 写入器})
+}
+
+// addSubst 在 ExtraFiles 中为s预留位置，并返回子进程中对应的路径。
+func (c *Cmd) addSubst(s *procSubst) string {
+	s.index = len(c.Cmd父类.ExtraFiles)
+	c.Cmd父类.ExtraFiles = append(c.Cmd父类.ExtraFiles, nil)
+	c.substs = append(c.substs, s)
+	return "/dev/fd/" + strconv.Itoa(3+s.index)
+}
+
+// prepareSubsts 创建各进程替换的管道，填入 ExtraFiles 中预留的位置，并启动其中的命令。
+func (c *Cmd) prepareSubsts() error {
+	for _, s := range c.substs {
+		pr, pw, err := os.Pipe()
+		if err != nil {
+			return err
+		}
+		if s.output {
+			s.child, s.other = pw, pr
+		} else {
+			s.child, s.other = pr, pw
+		}
+		c.Cmd父类.ExtraFiles[s.index] = s.child
+		if s.cmd == nil {
+			continue
+		}
+		if s.output {
+			if s.cmd.Cmd父类.Stdin != nil || s.cmd.stdin != nil {
+				return errors.New("exec: Stdin already set on process substitution")
+			}
+			s.cmd.Cmd父类.Stdin = s.other
+		} else {
+			if s.cmd.Cmd父类.Stdout != nil {
+				return errors.New("exec: Stdout already set on process substitution")
+			}
+			s.cmd.Cmd父类.Stdout = s.other
+		}
+		if err := s.cmd.I运行_异步(); err != nil {
+			s.cmd = nil
+			return err
+		}
+	}
+	return nil
+}
+
+// abortSubsts 在启动失败时关闭管道，并终止已启动的替换命令。
+func (c *Cmd) abortSubsts() {
+	for _, s := range c.substs {
+		if s.child == nil {
+			break
+		}
+		s.child.Close()
+		s.other.Close()
+		if s.cmd != nil && s.cmd.Cmd父类.Process != nil {
+			s.cmd.I终止()
+			s.cmd.I等待运行完成()
+		}
+	}
+	c.substs = nil
+}
+
+// startSubsts 在命令启动后关闭父进程持有的多余管道端，并启动复制协程。
+func (c *Cmd) startSubsts() {
+	for _, s := range c.substs {
+		s.child.Close()
+		if s.cmd != nil {
+			s.other.Close()
+			continue
+		}
+		s.errc = make(chan error, 1)
+		go func(s *procSubst) {
+			var err error
+			if s.output {
+				_, err = io.Copy(s.w, s.other)
+			} else {
+				_, err = io.Copy(s.other, s.r)
+			}
+			if cerr := s.other.Close(); err == nil {
+				err = cerr
+			}
+			s.errc <- err
+		}(s)
+	}
+}
+
+// waitSubsts 在命令退出后等待替换命令和复制协程结束，返回第一个复制错误。
+func (c *Cmd) waitSubsts() error {
+	var copyErr error
+	for _, s := range c.substs {
+		if s.cmd != nil {
+			s.cmd.I等待运行完成()
+			continue
+		}
+		err := <-s.errc
+		if err != nil && !s.output && c.skipStdinError(err) {
+			err = nil
+		}
+		if copyErr == nil {
+			copyErr = err
+		}
+	}
+	c.substs = nil
+	return copyErr
+}
@@ -0,0 +1,38 @@
+package cmd类
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// KillStep 是 I终止进程树 的信号序列中的一步：向进程树中仍存活的进程发送 Signal，
+// 然后最多等待 Wait，全部退出时提前结束。
+type KillStep struct {
+	Signal os.Signal
+	Wait   time.Duration
+}
+
+// I终止进程树 终止已启动的命令及其全部后代进程，返回发送过信号的进程号，命令本身排在第一个。
+//
+// 进程组无法覆盖调用了 setsid 的后代进程，因此它通过/proc中的父进程号找出后代：
+// 先用SIGSTOP冻结命令本身，再逐层找出并冻结子进程，直到进程树不再变化，冻结期间进程无法再创建子进程。
+// 然后依次执行 步骤：每一步先重新冻结并补充新出现的后代，向所有仍存活的进程发送信号，
+// 再发送SIGCONT使停止的进程能够处理信号（SIGKILL除外），并等待它们退出。
+// 没有指定 步骤 时先发送SIGTERM并等待2秒，再发送SIGKILL。僵尸进程视为已退出；
+// 每个进程都核对启动时间，已退出进程的进程号被重用时不会误发信号。
+//
+// 必须在 I等待运行完成 回收命令之前调用（例如在命令运行期间或上下文结束的处理中），
+// 回收之后后代进程已被收养，无法再找到。仅Linux支持，其他系统返回错误。
+func (c *Cmd) I终止进程树(步骤 ...KillStep) ([]int, error) {
+	if c == nil {
+		return nil, errors.New("cmd类对象为nil")
+	}
+	if c.Cmd父类.Process == nil {
+		return nil, errors.New("exec: not started")
+	}
+	if c.isReaped() {
+		return nil, errors.New("exec: Wait was already called")
+	}
+	return killTree(c.Cmd父类.Process.Pid, 步骤)
+}
@@ -0,0 +1,248 @@
+package cmd类
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// ErrReadyTimeout 是 I等待就绪 在命令就绪之前超时时返回的错误。命令不会被终止。
+var ErrReadyTimeout = errors.New("exec: command not ready before timeout")
+
+// ReadyError 是命令在就绪之前退出时 I等待就绪 返回的错误。
+type ReadyError struct {
+	Err error // I等待运行完成 将返回的错误，命令正常退出或尚未回收完毕时为nil
+}
+
+func (e *ReadyError) Error() string {
+	if e.Err == nil {
+		return "exec: command exited before becoming ready"
+	}
+	return "exec: command exited before becoming ready: " + e.Err.Error()
+}
+
+func (e *ReadyError) Unwrap() error { return e.Err }
+
+type readyKind int
+
+const (
+	readyLine readyKind = iota
+	readyTCP
+	readyUnix
+	readyFile
+)
+
+// ReadyCheck 是 I设置就绪条件 的一个就绪条件，由 I就绪_ 系列函数创建。
+type ReadyCheck struct {
+	kind readyKind
+	re   *regexp.Regexp
+	addr string
+}
+
+// I就绪_输出行 在命令的标准输出或标准错误中出现与 正则 匹配的一行时满足。
+// 只检查以换行结束的行，行尾的\r会被去掉；超过64KB的行不参与匹配。
+func I就绪_输出行(正则 *regexp.Regexp) ReadyCheck {
+	return ReadyCheck{kind: readyLine, re: 正则}
+}
+
+// I就绪_TCP端口 在 地址（如 "127.0.0.1:8080"，省略主机时为本机）可以建立TCP连接时满足。
+func I就绪_TCP端口(地址 string) ReadyCheck {
+	return ReadyCheck{kind: readyTCP, addr: 地址}
+}
+
+// I就绪_Unix套接字 在 路径 上的Unix域套接字可以连接时满足。相对路径以命令的 Cmd父类.Dir 为基准。
+func I就绪_Unix套接字(路径 string) ReadyCheck {
+	return ReadyCheck{kind: readyUnix, addr: 路径}
+}
+
+// I就绪_文件存在 在 路径 存在时满足，适合启动后写出pidfile或标记文件的命令。相对路径以命令的 Cmd父类.Dir 为基准。
+func I就绪_文件存在(路径 string) ReadyCheck {
+	return ReadyCheck{kind: readyFile, addr: 路径}
+}
+
+// I设置就绪条件 设置 I等待就绪 等待的条件，须在启动前调用，全部满足时命令视为就绪。
+// 条件无效（例如 I就绪_输出行 的正则为nil）时 I等待就绪 返回错误。
+//
+// 使用 I就绪_输出行 时本包需要观察命令的输出，与 I设置无输出超时 一样会在子进程和
+// Cmd父类.Stdout、Cmd父类.Stderr 之间插入管道，数据照常写入它们。
+func (c *Cmd) I设置就绪条件(条件 ...ReadyCheck) {
+	r := &readyState{checks: 条件, matched: make([]bool, len(条件)), changed: make(chan struct{})}
+	for i, ch := range 条件 {
+		if ch.kind != readyLine {
+			continue
+		}
+		if ch.re == nil {
+			// 不参与匹配，由 I等待就绪 报告。
+			r.matched[i] = true
+			r.err = errors.New("exec: nil regexp in readiness condition")
+			continue
+		}
+		r.pending++
+	}
+	c.ready = r
+}
+
+// I等待就绪 等待由 I运行_异步 启动的命令满足 I设置就绪条件 设置的全部条件，例如开发服务器开始监听端口。
+// 时长 为0表示不限制。
+//
+// 就绪之前超时时返回 ErrReadyTimeout，命令退出时返回 *ReadyError，两种情况都不会终止命令。
+// 为了发现命令退出，本包会在后台等待命令，此后仍须调用 I等待运行完成 取得结果，
+// 在此之前不要读取 Cmd父类.ProcessState。
+func (c *Cmd) I等待就绪(时长 time.Duration) error {
+	if c == nil {
+		return errors.New("cmd类对象为nil")
+	}
+	r := c.ready
+	if r == nil || len(r.checks) == 0 {
+		return errors.New("exec: no readiness conditions set")
+	}
+	if r.err != nil {
+		return r.err
+	}
+	w, err := c.backgroundWaiter()
+	if err != nil {
+		return err
+	}
+	var deadline time.Time
+	var timeout <-chan time.Time
+	if 时长 > 0 {
+		deadline = time.Now().Add(时长)
+		t := time.NewTimer(时长)
+		defer t.Stop()
+		timeout = t.C
+	}
+	exited := c.I退出通知()
+	delay := 10 * time.Millisecond
+	for {
+		changed, ok := r.check(c.Cmd父类.Dir, deadline)
+		if ok {
+			return nil
+		}
+		poll := time.NewTimer(delay)
+		select {
+		case <-poll.C:
+		case <-changed:
+			poll.Stop()
+		case <-exited:
+			poll.Stop()
+			select {
+			case <-w.done:
+				return &ReadyError{Err: w.err}
+			default:
+				return &ReadyError{}
+			}
+		case <-w.done:
+			poll.Stop()
+			return &ReadyError{Err: w.err}
+		case <-timeout:
+			poll.Stop()
+			return ErrReadyTimeout
+		}
+		delay = min(delay*2, 200*time.Millisecond)
+	}
+}
+
+// maxReadyLine 是 I就绪_输出行 检查的最长行。
+const maxReadyLine = 64 << 10
+
+// readyState 记录命令的就绪条件和输出行条件的进展。
+type readyState struct {
+	checks []ReadyCheck
+	err    error // 无效条件的错误
+
+	mu      sync.Mutex
+	lines   [3][]byte     // 各输出流中尚未结束的行
+	matched []bool        // 各输出行条件是否已满足
+	pending int           // 尚未满足的输出行条件数
+	changed chan struct{} // 有输出行条件满足时关闭并替换
+}
+
+// needsOutput 报告是否有条件需要观察命令的输出。
+func (r *readyState) needsOutput() bool {
+	return r != nil && r.pending > 0
+}
+
+// scan 检查子进程在stream上写出的数据中新结束的行。
+func (r *readyState) scan(stream int, p []byte) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.pending == 0 {
+		return
+	}
+	buf := append(r.lines[stream], p...)
+	for {
+		i := bytes.IndexByte(buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := bytes.TrimSuffix(buf[:i], []byte("\r"))
+		buf = buf[i+1:]
+		if len(line) > maxReadyLine {
+			continue
+		}
+		for j, ch := range r.checks {
+			if ch.kind == readyLine && !r.matched[j] && ch.re.Match(line) {
+				r.matched[j] = true
+				r.pending--
+				close(r.changed)
+				r.changed = make(chan struct{})
+			}
+		}
+		if r.pending == 0 {
+			r.lines = [3][]byte{}
+			return
+		}
+	}
+	if len(buf) > maxReadyLine {
+		// 过长的行不参与匹配，只保留末尾以便找到它的结束。
+		buf = buf[len(buf)-maxReadyLine-1:]
+	}
+	r.lines[stream] = append(r.lines[stream][:0], buf...)
+}
+
+// check 报告是否全部条件都已满足，未满足时返回输出行条件有进展时关闭的通道。
+// 相对路径以dir为基准，连接在deadline（非零时）之后不再等待。
+func (r *readyState) check(dir string, deadline time.Time) (<-chan struct{}, bool) {
+	r.mu.Lock()
+	changed := r.changed
+	lines := r.pending == 0
+	r.mu.Unlock()
+	if !lines {
+		return changed, false
+	}
+	for _, ch := range r.checks {
+		switch ch.kind {
+		case readyTCP:
+			if !canDial("tcp", ch.addr, deadline) {
+				return changed, false
+			}
+		case readyUnix:
+			if !canDial("unix", fsPath(dir, ch.addr), deadline) {
+				return changed, false
+			}
+		case readyFile:
+			if _, err := os.Stat(fsPath(dir, ch.addr)); err != nil {
+				return changed, false
+			}
+		}
+	}
+	return changed, true
+}
+
+// canDial 报告能否连接到address。
+func canDial(network, address string, deadline time.Time) bool {
+	d := net.Dialer{Timeout: time.Second, Deadline: deadline}
+	conn, err := d.Dial(network, address)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
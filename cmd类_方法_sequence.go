@@ -0,0 +1,131 @@
+package cmd类
+
+// seqOp 是 Sequence 中步骤与前一步骤之间的连接方式。
+type seqOp int
+
+const (
+	seqAlways seqOp = iota // ;
+	seqAnd                 // &&
+	seqOr                  // ||
+)
+
+// Step状态 表示 Sequence 中一个步骤的执行情况。
+type Step状态 int
+
+const (
+	// Step_未执行 表示步骤因 I设置出错即停 而未执行。
+	Step_未执行 Step状态 = iota
+
+	// Step_已跳过 表示步骤因 && 或 || 的条件不满足而被跳过。
+	Step_已跳过
+
+	// Step_已运行 表示步骤已运行，结果见 SequenceStep.Err。
+	Step_已运行
+)
+
+// Sequence 依次运行一组命令，命令之间可以像shell一样用 &&、|| 或 ; 连接，例如：
+//
+//	结果, err := cmd类.I设置序列(cmd类.I设置命令("make")).
+//		I并且(cmd类.I设置命令("make", "test")).
+//		I或者(cmd类.I设置命令("notify-send", "failed")).
+//		I运行()
+//
+// 与shell一样，被跳过的步骤不改变最近一次的退出状态，因此 a && b || c 在a失败时运行c。
+type Sequence struct {
+	cmds    []*Cmd
+	ops     []seqOp
+	errExit bool
+	dir     string
+	env     []string
+}
+
+// SequenceStep 报告 Sequence 中一个步骤的结果。
+type SequenceStep struct {
+	Cmd   *Cmd
+	State Step状态
+	// Err 是步骤运行的结果，成功或未运行时为nil。
+	Err error
+}
+
+// SequenceResult 报告 Sequence 各步骤的结果。
+type SequenceResult struct {
+	// Steps 与添加的步骤一一对应。
+	Steps []SequenceStep
+	// Aborted 报告序列是否因 I设置出错即停 而提前结束。
+	Aborted bool
+}
+
+// I设置序列 返回以 命令 为第一步的 Sequence。
+func I设置序列(命令 *Cmd) *Sequence {
+	return &Sequence{cmds: []*Cmd{命令}, ops: []seqOp{seqAlways}}
+}
+
+// I然后 添加一个无论前面结果如何都会运行的步骤，相当于shell中的 ;。
+func (s *Sequence) I然后(命令 *Cmd) *Sequence {
+	return s.add(seqAlways, 命令)
+}
+
+// I并且 添加一个仅当最近一次的退出状态为成功时才运行的步骤，相当于shell中的 &&。
+func (s *Sequence) I并且(命令 *Cmd) *Sequence {
+	return s.add(seqAnd, 命令)
+}
+
+// I或者 添加一个仅当最近一次的退出状态为失败时才运行的步骤，相当于shell中的 ||。
+func (s *Sequence) I或者(命令 *Cmd) *Sequence {
+	return s.add(seqOr, 命令)
+}
+
+func (s *Sequence) add(op seqOp, c *Cmd) *Sequence {
+	s.cmds = append(s.cmds, c)
+	s.ops = append(s.ops, op)
+	return s
+}
+
+// I设置出错即停 设置是否像shell的 set -e 一样在步骤失败时停止运行后续步骤。
+//
+// 与shell一样，后面紧跟 && 或 || 步骤的失败不会导致停止，只有 && / || 链中最后一个步骤的失败才会。
+func (s *Sequence) I设置出错即停(出错即停 bool) *Sequence {
+	s.errExit = 出错即停
+	return s
+}
+
+// I设置默认目录 设置步骤的默认工作目录，仅作用于 Cmd父类.Dir 为空的步骤。
+func (s *Sequence) I设置默认目录(目录 string) *Sequence {
+	s.dir = 目录
+	return s
+}
+
+// I设置默认环境变量 设置步骤的默认环境变量，仅作用于 Cmd父类.Env 为nil的步骤。
+func (s *Sequence) I设置默认环境变量(环境变量 []string) *Sequence {
+	s.env = 环境变量
+	return s
+}
+
+// I运行 依次运行各步骤并返回每个步骤的结果。
+//
+// 返回的错误相当于shell中序列结束时的 $?：最近一次运行的步骤的错误，因出错即停而结束时为导致停止的错误。
+func (s *Sequence) I运行() (*SequenceResult, error) {
+	res := &SequenceResult{Steps: make([]SequenceStep, len(s.cmds))}
+	var last error
+	for i, c := range s.cmds {
+		res.Steps[i].Cmd = c
+		if (s.ops[i] == seqAnd && last != nil) || (s.ops[i] == seqOr && last == nil) {
+			res.Steps[i].State = Step_已跳过
+			continue
+		}
+		if c.Cmd父类.Dir == "" {
+			c.Cmd父类.Dir = s.dir
+		}
+		if c.Cmd父类.Env == nil {
+			c.Cmd父类.Env = s.env
+		}
+		last = c.I运行()
+		res.Steps[i].State = Step_已运行
+		res.Steps[i].Err = last
+		if last != nil && s.errExit && (i+1 == len(s.cmds) || s.ops[i+1] == seqAlways) {
+			res.Aborted = i+1 < len(s.cmds)
+			break
+		}
+	}
+	return res, last
+}
@@ -0,0 +1,155 @@
+package cmd类
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// redirect 是解析命令行得到的一个重定向，在启动时按顺序应用。
+type redirect struct {
+	fd    int    // 0、1或2
+	op    string // "<", ">", ">>", ">&"
+	path  string // op为">&"以外时的文件名
+	dupFd int    // op为">&"时的目标描述符
+}
+
+// I解析命令行 把受限的POSIX shell命令行解析为可以直接执行的 Pipeline，不调用任何shell。
+//
+// 支持的语法：空白分隔的词，单引号、双引号和反斜杠转义，用 | 连接的管道，
+// 重定向 <、>、>>（可带描述符前缀，如 2>）、2>&1 和 >&2，以及命令名前的 NAME=value 环境变量赋值。
+// 例如：
+//
+//	p, err := cmd类.I解析命令行("LC_ALL=C grep -v foo input.txt | sort > out.txt")
+//
+// 参数展开（$）、命令替换（`...` 和 $(...)）、glob模式、~、注释、;、&、&&、||、子shell和here文档等
+// 会被shell赋予其他含义的语法均返回 *ParseError，而不是原样作为参数传递。
+//
+// 没有重定向时，最后一段的标准输出和各段的标准错误与 I设置命令 创建的命令一样为空，由调用方按需设置。
+// 重定向的文件在启动时才打开，相对路径以该段的 Cmd父类.Dir 为基准；与shell一样，重定向优先于管道连接。
+// 每段的程序名在解析时用 I查找路径 解析。
+func I解析命令行(命令行 string) (*Pipeline, error) {
+	cmds, err := parseCommandLine(命令行)
+	if err != nil {
+		return nil, err
+	}
+	return I设置管道(cmds...), nil
+}
+
+// I解析命令行_上下文 与 I解析命令行 类似，但返回的 Pipeline 在上下文完成时终止所有段。
+func I解析命令行_上下文(上下文 context.Context, 命令行 string) (*Pipeline, error) {
+	cmds, err := parseCommandLine(命令行)
+	if err != nil {
+		return nil, err
+	}
+	return I设置管道_上下文(上下文, cmds...), nil
+}
+
+func parseCommandLine(line string) ([]*Cmd, error) {
+	l := &shLexer{in: line, strict: true}
+	toks, err := l.lex()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		cmds   []*Cmd
+		env    []string
+		args   []string
+		redirs []redirect
+	)
+	finish := func(pos int) error {
+		if len(args) == 0 {
+			return l.errorf(pos, "missing command")
+		}
+		c := I设置命令(args[0], args[1:]...)
+		if len(env) > 0 {
+			c.Cmd父类.Env = append(c.Cmd父类.Environ(), env...)
+		}
+		c.redirs = redirs
+		cmds = append(cmds, c)
+		env, args, redirs = nil, nil, nil
+		return nil
+	}
+	for i := 0; i < len(toks); i++ {
+		t := toks[i]
+		switch t.kind {
+		case shPipe:
+			if err := finish(t.pos); err != nil {
+				return nil, err
+			}
+		case shRedir:
+			r := redirect{fd: t.fd, op: t.op, dupFd: t.dupFd}
+			if t.op != ">&" {
+				if i+1 >= len(toks) || toks[i+1].kind != shWord {
+					return nil, l.errorf(t.pos, "missing file name for redirection "+t.op)
+				}
+				i++
+				r.path = toks[i].word
+			}
+			redirs = append(redirs, r)
+		case shWord:
+			if len(args) == 0 && t.assign >= 0 {
+				env = append(env, t.word)
+			} else {
+				args = append(args, t.word)
+			}
+		}
+	}
+	if err := finish(len(line)); err != nil {
+		return nil, err
+	}
+	return cmds, nil
+}
+
+// prepareRedirects 按顺序打开重定向的文件并设置 Cmd父类 的标准输入、输出和错误。
+func (c *Cmd) prepareRedirects() error {
+	for _, r := range c.redirs {
+		if r.op == ">&" {
+			// 1>&1 和 2>&2 不改变任何描述符。
+			switch {
+			case r.fd == 2 && r.dupFd == 1:
+				c.Cmd父类.Stderr = c.Cmd父类.Stdout
+			case r.fd == 1 && r.dupFd == 2:
+				c.Cmd父类.Stdout = c.Cmd父类.Stderr
+			}
+			continue
+		}
+		path := r.path
+		if c.Cmd父类.Dir != "" && !filepath.IsAbs(path) {
+			path = filepath.Join(c.Cmd父类.Dir, path)
+		}
+		flag := os.O_RDONLY
+		switch r.op {
+		case ">":
+			flag = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		case ">>":
+			flag = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+		}
+		f, err := os.OpenFile(path, flag, 0666)
+		if err != nil {
+			return err
+		}
+		c.redirFiles = append(c.redirFiles, f)
+		switch r.fd {
+		case 0:
+			if c.stdin != nil {
+				return errors.New("exec: Stdin already set")
+			}
+			c.Cmd父类.Stdin = f
+		case 1:
+			c.Cmd父类.Stdout = f
+		case 2:
+			c.Cmd父类.Stderr = f
+		}
+	}
+	return nil
+}
+
+// closeRedirects 关闭父进程持有的重定向文件。
+func (c *Cmd) closeRedirects() {
+	for _, f := range c.redirFiles {
+		f.Close()
+	}
+	c.redirFiles = nil
+}
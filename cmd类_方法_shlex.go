@@ -0,0 +1,139 @@
+package cmd类
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// I分割参数 按POSIX shell的规则把 命令行 分割为参数列表，可以直接用作 I设置命令 的参数。
+//
+// 它识别单引号、双引号、反斜杠转义和续行，空格、制表符和换行分隔参数。
+// 与 I解析命令行 不同，它不解释任何操作符和展开：$、*、|、> 等字符原样保留在参数中。
+// 引号不匹配或以单个反斜杠结尾时返回 *ParseError。
+func I分割参数(命令行 string) ([]string, error) {
+	l := &shLexer{in: 命令行}
+	toks, err := l.lex()
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, len(toks))
+	for i, t := range toks {
+		args[i] = t.word
+	}
+	return args, nil
+}
+
+// I引用参数 返回 参数 在POSIX shell中的安全写法：shell会把它解析为与 参数 完全相同的一个词。
+//
+// 只含常见安全字符的参数原样返回，其他参数用单引号括起，其中的单引号写作：
+//
+//	'\''
+func I引用参数(参数 string) string {
+	if 参数 == "" {
+		return "''"
+	}
+	if isShellSafe(参数) {
+		return 参数
+	}
+	return "'" + strings.ReplaceAll(参数, "'", `'\''`) + "'"
+}
+
+// I拼接参数 用 I引用参数 引用每个参数后以空格连接，结果可以粘贴到终端中执行。
+// I分割参数 是它的逆运算。
+//
+// 第一个参数含有 = 时总是加引号，以免shell把它当作环境变量赋值。
+func I拼接参数(参数组 []string) string {
+	quoted := make([]string, len(参数组))
+	for i, arg := range 参数组 {
+		quoted[i] = I引用参数(arg)
+		if i == 0 && quoted[i] == arg && strings.Contains(arg, "=") {
+			quoted[i] = "'" + arg + "'"
+		}
+	}
+	return strings.Join(quoted, " ")
+}
+
+// ShellString 返回可以粘贴到POSIX shell中执行的等价命令。
+//
+// 与 I取命令 不同，每个参数都经过 I引用参数 引用。设置了 Cmd父类.Dir 时命令形如 (cd DIR && ...)，
+// 以免改变当前shell的工作目录。设置了 Cmd父类.Env 时，与当前进程环境相比新增或改变的变量
+// 以 NAME=value 前缀写出，被删除的变量以 env -u NAME 写出。
+// 标准输入输出、ExtraFiles 和 SysProcAttr 等设置无法用命令行表示，会被忽略。
+func (c *Cmd) ShellString() string {
+	if c == nil {
+		return ""
+	}
+	var b strings.Builder
+	if c.Cmd父类.Dir != "" {
+		b.WriteString("(cd ")
+		b.WriteString(I引用参数(c.Cmd父类.Dir))
+		b.WriteString(" && ")
+	}
+	if c.Cmd父类.Env != nil {
+		set, unset := envDiff(os.Environ(), c.Cmd父类.Env)
+		if len(unset) > 0 {
+			b.WriteString("env")
+			for _, name := range unset {
+				b.WriteString(" -u ")
+				b.WriteString(I引用参数(name))
+			}
+			b.WriteString(" ")
+		}
+		for _, kv := range set {
+			name, value, _ := strings.Cut(kv, "=")
+			b.WriteString(name)
+			b.WriteString("=")
+			b.WriteString(I引用参数(value))
+			b.WriteString(" ")
+		}
+	}
+	args := []string{c.Cmd父类.Path}
+	if len(c.Cmd父类.Args) > 1 {
+		args = append(args, c.Cmd父类.Args[1:]...)
+	}
+	b.WriteString(I拼接参数(args))
+	if c.Cmd父类.Dir != "" {
+		b.WriteString(")")
+	}
+	return b.String()
+}
+
+// envDiff 返回把环境from变为to需要设置的 NAME=value 项和需要删除的变量名，均按名称排序。
+// 与 os/exec 一样，to中同名的项以最后一个为准；名称不合法的项无法写成赋值，会被忽略。
+func envDiff(from, to []string) (set, unset []string) {
+	parse := func(env []string) map[string]string {
+		m := make(map[string]string, len(env))
+		for _, kv := range env {
+			if name, value, ok := strings.Cut(kv, "="); ok && isName(name) {
+				m[name] = value
+			}
+		}
+		return m
+	}
+	old, cur := parse(from), parse(to)
+	for name, value := range cur {
+		if v, ok := old[name]; !ok || v != value {
+			set = append(set, name+"="+value)
+		}
+	}
+	for name := range old {
+		if _, ok := cur[name]; !ok {
+			unset = append(unset, name)
+		}
+	}
+	sort.Strings(set)
+	sort.Strings(unset)
+	return set, unset
+}
+
+// isShellSafe 报告s是否只含无需引用的字符。
+func isShellSafe(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("@%+=:,./-_", c) >= 0) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,290 @@
+package cmd类
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync"
+)
+
+// Stdin断管策略 决定向子进程标准输入写入时遇到断管错误（Unix上的EPIPE）如何处理。
+// 子进程不读完标准输入就退出时会出现这种错误。
+type Stdin断管策略 int
+
+const (
+	// Stdin断管_成功时忽略 子进程以零状态退出时忽略断管错误，这是默认策略，与 os/exec 一致。
+	// 对 I取Stdin管道 返回的写入端，写入时仍直接返回该错误。
+	Stdin断管_成功时忽略 Stdin断管策略 = iota
+
+	// Stdin断管_总是忽略 总是忽略断管错误。I取Stdin管道 返回的写入端遇到断管时丢弃数据并报告写入成功。
+	Stdin断管_总是忽略
+
+	// Stdin断管_总是报告 即使子进程以零状态退出，I运行 和 I等待运行完成 也返回断管错误。
+	// 通过 I取Stdin管道 写入时遇到的断管错误同样会在等待时再次返回。
+	Stdin断管_总是报告
+)
+
+// stdinSource 描述由 I设置Stdin_ 系列方法指定的标准输入来源。
+type stdinSource struct {
+	open func() (f *os.File, cleanup func(), err error) // 非nil时其返回的文件直接作为子进程的标准输入
+	gen  func(w io.Writer, stop <-chan struct{}) error  // 否则经管道向子进程写入；进程退出后stop被关闭
+}
+
+// stdinFeeder 保存一次运行中标准输入所用的文件和写入协程的状态。
+type stdinFeeder struct {
+	child   *os.File // 交给子进程的一端，启动后在父进程中关闭
+	pw      *os.File // 管道写入端，open来源时为nil
+	cleanup func()   // open来源在进程退出后需要的清理，可为nil
+	gen     func(w io.Writer, stop <-chan struct{}) error
+	stop    chan struct{}
+	errc    chan error
+}
+
+// I设置Stdin_文本 使命令启动后从 文本 读取标准输入。
+//
+// 文本经管道写入，写完后关闭写入端，写入错误由 I运行 或 I等待运行完成 返回。
+// 与 Cmd父类.Stdin 或 I取Stdin管道 同时使用时，I运行_异步 返回错误。
+func (c *Cmd) I设置Stdin_文本(文本 string) {
+	c.stdin = &stdinSource{gen: func(w io.Writer, _ <-chan struct{}) error {
+		_, err := io.WriteString(w, 文本)
+		return err
+	}}
+}
+
+// I设置Stdin_字节集 使命令启动后从 数据 读取标准输入。
+//
+// 命令启动前不应再修改 数据。其余行为同 I设置Stdin_文本。
+func (c *Cmd) I设置Stdin_字节集(数据 []byte) {
+	c.stdin = &stdinSource{gen: func(w io.Writer, _ <-chan struct{}) error {
+		_, err := w.Write(数据)
+		return err
+	}}
+}
+
+// I设置Stdin_文件 使命令以 文件路径 指向的文件作为标准输入。
+//
+// 文件在启动时打开，其描述符直接交给子进程，数据不经过本进程复制。
+// 打开失败时 I运行_异步 返回该错误。
+func (c *Cmd) I设置Stdin_文件(文件路径 string) {
+	c.stdin = &stdinSource{open: func() (*os.File, func(), error) {
+		f, err := os.Open(文件路径)
+		return f, nil, err
+	}}
+}
+
+// I设置Stdin_内存文件 使命令以内容为 数据 的只读普通文件作为标准输入。
+//
+// 与管道不同，子进程可以对这样的标准输入执行seek和stat，得到准确的大小。
+// 在Linux上使用封印（sealed）的匿名memfd，不落盘；其他系统或memfd不可用时，
+// 改用写入临时目录后设为只读的文件，并在进程退出后删除。
+func (c *Cmd) I设置Stdin_内存文件(数据 []byte) {
+	c.stdin = &stdinSource{open: func() (*os.File, func(), error) {
+		return newMemFile(数据)
+	}}
+}
+
+// I设置Stdin_生成函数 使命令启动后在单独的协程中调用 生成函数，它写入 写入器 的数据即为子进程的标准输入。
+//
+// 生成函数返回后写入端随即关闭，子进程读到EOF。生成函数返回的错误或关闭写入端的错误由 I等待运行完成 返回，
+// 但进程本身的错误优先。子进程提前退出时，后续写入会返回断管错误，生成函数应在写入出错时返回。
+func (c *Cmd) I设置Stdin_生成函数(生成函数 func(写入器 io.Writer) error) {
+	c.stdin = &stdinSource{gen: func(w io.Writer, _ <-chan struct{}) error {
+		return 生成函数(w)
+	}}
+}
+
+// I设置Stdin_行通道 使命令启动后把从 行通道 收到的每个元素作为一行写入标准输入，每行末尾追加换行符。
+//
+// 行通道关闭后写入端随即关闭。子进程退出后不再从 行通道 接收，因此未关闭的通道不会阻塞 I等待运行完成。
+func (c *Cmd) I设置Stdin_行通道(行通道 <-chan string) {
+	c.stdin = &stdinSource{gen: func(w io.Writer, stop <-chan struct{}) error {
+		for {
+			select {
+			case line, ok := <-行通道:
+				if !ok {
+					return nil
+				}
+				if _, err := io.WriteString(w, line+"\n"); err != nil {
+					return err
+				}
+			case <-stop:
+				return nil
+			}
+		}
+	}}
+}
+
+// I设置Stdin断管策略 设置写入标准输入遇到断管错误时的处理方式，须在命令启动前调用。
+//
+// 策略作用于 I设置Stdin_ 系列方法、Cmd父类.Stdin 中的 io.Reader 以及 I取Stdin管道 返回的写入端。
+// Cmd父类.Stdin 为 *os.File 时数据不经过本进程，策略不起作用。
+func (c *Cmd) I设置Stdin断管策略(策略 Stdin断管策略) {
+	c.stdinPolicy = 策略
+}
+
+// prepareStdin 为 c.stdin 打开文件或创建管道，并设置 Cmd父类.Stdin。
+func (c *Cmd) prepareStdin() error {
+	if c.stdin == nil && c.stdinPolicy == Stdin断管_总是报告 {
+		// os/exec 自行复制时总会在成功时忽略断管错误，因此改由本包复制。
+		if r := c.Cmd父类.Stdin; r != nil {
+			if _, ok := r.(*os.File); !ok {
+				c.Cmd父类.Stdin = nil
+				c.stdin = &stdinSource{gen: func(w io.Writer, _ <-chan struct{}) error {
+					_, err := io.Copy(w, r)
+					return err
+				}}
+			}
+		}
+	}
+	if c.stdin == nil {
+		return nil
+	}
+	if c.Cmd父类.Stdin != nil {
+		return errors.New("exec: Stdin already set")
+	}
+	if c.stdin.open != nil {
+		f, cleanup, err := c.stdin.open()
+		if err != nil {
+			return err
+		}
+		c.Cmd父类.Stdin = f
+		c.stdinFeeder = &stdinFeeder{child: f, cleanup: cleanup}
+		return nil
+	}
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	c.Cmd父类.Stdin = pr
+	c.stdinFeeder = &stdinFeeder{child: pr, pw: pw, gen: c.stdin.gen}
+	return nil
+}
+
+// abortStdin 在启动失败时关闭 prepareStdin 打开的文件。
+func (c *Cmd) abortStdin() {
+	f := c.stdinFeeder
+	if f == nil {
+		return
+	}
+	c.stdinFeeder = nil
+	f.child.Close()
+	if f.pw != nil {
+		f.pw.Close()
+	}
+	if f.cleanup != nil {
+		f.cleanup()
+	}
+}
+
+// startStdin 在进程启动后关闭父进程持有的子进程一端，并启动写入协程。
+func (c *Cmd) startStdin() {
+	f := c.stdinFeeder
+	if f == nil {
+		return
+	}
+	f.child.Close()
+	if f.pw == nil {
+		if f.cleanup == nil {
+			c.stdinFeeder = nil
+		}
+		return
+	}
+	f.stop = make(chan struct{})
+	f.errc = make(chan error, 1)
+	go func() {
+		err := f.gen(stdinWriter{f.pw}, f.stop)
+		if cerr := f.pw.Close(); err == nil {
+			err = cerr
+		}
+		f.errc <- err
+	}()
+}
+
+// waitStdin 在进程退出后等待写入协程结束，并按 Stdin断管策略 返回它的错误。
+func (c *Cmd) waitStdin() error {
+	if f := c.stdinFeeder; f != nil && f.pw == nil {
+		c.stdinFeeder = nil
+		f.cleanup()
+	} else if f != nil {
+		c.stdinFeeder = nil
+		close(f.stop)
+		if err := <-f.errc; err != nil && !c.skipStdinError(err) {
+			return err
+		}
+	}
+	if w := c.stdinPipe; w != nil {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return w.err
+	}
+	return nil
+}
+
+// skipStdinError 报告在子进程成功退出的前提下是否应忽略复制stdin时的错误err。
+func (c *Cmd) skipStdinError(err error) bool {
+	return c.stdinPolicy != Stdin断管_总是报告 && skipStdinCopyError(err)
+}
+
+// stdinWriter 对生成函数隐藏底层的 *os.File，避免其被提前关闭。
+type stdinWriter struct {
+	w io.Writer
+}
+
+func (w stdinWriter) Write(p []byte) (int, error) {
+	return w.w.Write(p)
+}
+
+// stdinPipeWriter 包装 I取Stdin管道 返回的写入端，按 Stdin断管策略 处理断管错误。
+type stdinPipeWriter struct {
+	io.WriteCloser
+	c *Cmd
+
+	mu  sync.Mutex
+	err error // Stdin断管_总是报告 策略下记录的第一个断管错误
+}
+
+func (w *stdinPipeWriter) Write(p []byte) (int, error) {
+	n, err := w.WriteCloser.Write(p)
+	if err == nil || !skipStdinCopyError(err) {
+		return n, err
+	}
+	switch w.c.stdinPolicy {
+	case Stdin断管_总是忽略:
+		return len(p), nil
+	case Stdin断管_总是报告:
+		w.mu.Lock()
+		if w.err == nil {
+			w.err = err
+		}
+		w.mu.Unlock()
+	}
+	return n, err
+}
+
+// tempMemFile 把data写入临时目录中的文件，设为只读后重新以只读方式打开。
+// 能删除已打开文件的系统上文件随即被删除，否则返回的cleanup在进程退出后删除它。
+func tempMemFile(data []byte) (f *os.File, cleanup func(), err error) {
+	w, err := os.CreateTemp("", "cmd类-stdin-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	name := w.Name()
+	_, err = w.Write(data)
+	if cerr := w.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(name, 0400)
+	}
+	if err == nil {
+		f, err = os.Open(name)
+	}
+	if err != nil {
+		os.Remove(name)
+		return nil, nil, err
+	}
+	if os.Remove(name) == nil {
+		return f, nil, nil
+	}
+	return f, func() { os.Remove(name) }, nil
+}
@@ -0,0 +1,72 @@
+package cmd类
+
+import "sync"
+
+// AdoptedProcess 描述收养回收模式下被回收的一个被收养进程。
+type AdoptedProcess struct {
+	Pid      int
+	Name     string // /proc/PID/comm中的进程名，可能被截断
+	ExitCode int    // 与 os.ProcessState.ExitCode 相同，被信号终止时为-1
+}
+
+// I启用收养回收 使当前进程成为子进程收割者（Linux的PR_SET_CHILD_SUBREAPER）：
+// 由本进程启动的进程的后代失去父进程后（例如两次fork的守护进程）被本进程收养而不是交给init，
+// 本包在它们退出后回收，以免留下僵尸进程。每回收一个被收养的进程就在回收协程中调用 回调（可以为nil）。
+//
+// 回收只针对尚未由 Cmd 等待的进程：通过本包启动的命令的退出状态仍由 I等待运行完成 取得，不会被抢走。
+// 不经本包直接用 os/exec 或 os.StartProcess 启动的子进程在此期间也可能被当作被收养的进程回收，
+// 因此启用后应通过本包启动所有子进程。
+//
+// 这是进程级的设置，重复启用只替换 回调。仅Linux支持，其他系统返回错误。
+func I启用收养回收(回调 func(AdoptedProcess)) error {
+	return enableSubreaper(回调)
+}
+
+// I停用收养回收 取消 I启用收养回收 的设置并停止回收。已被收养的进程仍是本进程的子进程。
+func I停用收养回收() error {
+	return disableSubreaper()
+}
+
+// startGate 防止回收协程在子进程启动后、登记到 ownChildren 之前把它当作被收养的进程回收。
+// 启动命令时持有读锁，回收时持有写锁。
+var startGate sync.RWMutex
+
+var (
+	ownMu       sync.Mutex
+	ownChildren = make(map[int]bool) // 通过本包启动、尚未等待的子进程
+)
+
+// startProcess 启动 Cmd父类 并登记子进程。
+func (c *Cmd) startProcess() error {
+	startGate.RLock()
+	defer startGate.RUnlock()
+	if err := c.Cmd父类.Start(); err != nil {
+		return err
+	}
+	ownMu.Lock()
+	ownChildren[c.Cmd父类.Process.Pid] = true
+	ownMu.Unlock()
+	return nil
+}
+
+// waitProcess 等待 Cmd父类 并取消登记。
+func (c *Cmd) waitProcess() error {
+	if c.awaitExit() {
+		// 在回收之前停止转发，以免把信号发给可能已被重用的进程号。
+		c.stopForward()
+	}
+	err := c.Cmd父类.Wait()
+	if c.Cmd父类.ProcessState != nil {
+		ownMu.Lock()
+		delete(ownChildren, c.Cmd父类.Process.Pid)
+		ownMu.Unlock()
+	}
+	return err
+}
+
+// isOwnChild 报告pid是否是通过本包启动、尚未等待的子进程。
+func isOwnChild(pid int) bool {
+	ownMu.Lock()
+	defer ownMu.Unlock()
+	return ownChildren[pid]
+}
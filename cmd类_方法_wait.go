@@ -0,0 +1,105 @@
+package cmd类
+
+import (
+	"errors"
+	"reflect"
+	"time"
+)
+
+// ErrWaitTimeout 是 I等待运行完成_超时 在命令退出之前超时时返回的错误。命令不会被终止。
+var ErrWaitTimeout = errors.New("exec: wait timed out")
+
+// waiter 是对命令的唯一一次等待。I等待运行完成 在调用方的协程中等待，
+// I等待运行完成_超时 等则在后台协程中等待，以便在超时后继续。
+type waiter struct {
+	done      chan struct{}
+	err       error
+	collected bool // 结果已由某个等待方法返回给调用方，受 Cmd.waitMu 保护
+}
+
+// getWaiter 返回命令的waiter，第一次调用时创建。created报告是否是这次创建的，此时由调用方负责执行等待。
+func (c *Cmd) getWaiter() (w *waiter, created bool) {
+	c.waitMu.Lock()
+	defer c.waitMu.Unlock()
+	if c.waiter == nil {
+		c.waiter = &waiter{done: make(chan struct{})}
+		return c.waiter, true
+	}
+	return c.waiter, false
+}
+
+// run 执行等待并发布结果。
+func (w *waiter) run(c *Cmd) {
+	stop := c.watchGroupContext()
+	w.err = c.afterWait(c.waitProcess())
+	stop()
+	close(w.done)
+}
+
+// backgroundWaiter 返回命令的waiter，必要时在后台开始等待。
+func (c *Cmd) backgroundWaiter() (*waiter, error) {
+	if c.Cmd父类.Process == nil {
+		return nil, errors.New("exec: not started")
+	}
+	w, created := c.getWaiter()
+	if created {
+		go w.run(c)
+	}
+	return w, nil
+}
+
+// collect 把已完成的等待结果交给调用方。结果只能取得一次，与 Cmd父类.Wait 一样，之后的调用返回错误。
+func (c *Cmd) collect(w *waiter) error {
+	c.waitMu.Lock()
+	defer c.waitMu.Unlock()
+	if w.collected {
+		return errors.New("exec: Wait was already called")
+	}
+	w.collected = true
+	return w.err
+}
+
+// I等待运行完成_超时 与 I等待运行完成 相同，但最多等待 时长。命令在此之前没有退出时返回 ErrWaitTimeout，
+// 命令继续运行，之后可以再次调用 I等待运行完成_超时 或 I等待运行完成 取得结果。
+// 超时后后台仍在等待命令，在某个等待方法返回结果之前不要读取 Cmd父类.ProcessState。
+func (c *Cmd) I等待运行完成_超时(时长 time.Duration) error {
+	if c == nil {
+		return errors.New("cmd类对象为nil")
+	}
+	w, err := c.backgroundWaiter()
+	if err != nil {
+		return err
+	}
+	t := time.NewTimer(时长)
+	defer t.Stop()
+	select {
+	case <-w.done:
+		return c.collect(w)
+	case <-t.C:
+		return ErrWaitTimeout
+	}
+}
+
+// I等待任一 等待 命令组 中任一命令退出，返回它和 I等待运行完成 对它的结果，退出状态见其 Cmd父类.ProcessState。
+// 命令组 中的命令都必须已经启动；其余命令在后台继续被等待，之后仍可对它们调用 I等待运行完成 等方法。
+// 已经取得过结果的命令会立即被返回（并得到 "Wait was already called" 错误），反复调用时应从 命令组 中去掉返回的命令。
+func I等待任一(命令组 ...*Cmd) (*Cmd, error) {
+	if len(命令组) == 0 {
+		return nil, errors.New("exec: no commands to wait for")
+	}
+	cases := make([]reflect.SelectCase, len(命令组))
+	waiters := make([]*waiter, len(命令组))
+	for i, c := range 命令组 {
+		if c == nil {
+			return nil, errors.New("cmd类对象为nil")
+		}
+		w, err := c.backgroundWaiter()
+		if err != nil {
+			return c, err
+		}
+		waiters[i] = w
+		cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(w.done)}
+	}
+	i, _, _ := reflect.Select(cases)
+	return 命令组[i], 命令组[i].collect(waiters[i])
+}
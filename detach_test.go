@@ -0,0 +1,87 @@
+//go:build linux
+
+package cmd类
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// waitFile 等待文件name的内容满足ok，返回最后读到的内容。
+func waitFile(name string, ok func(string) bool) string {
+	var s string
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		b, _ := os.ReadFile(name)
+		if s = string(b); ok(s) {
+			break
+		}
+	}
+	return s
+}
+
+func TestDetach(t *testing.T) {
+	for _, doubleFork := range []bool{false, true} {
+		dir := t.TempDir()
+		log := filepath.Join(dir, "out.log")
+		pidfile := filepath.Join(dir, "run.pid")
+		c := I设置命令("sh", "-c", `echo $$; echo err >&2; read x || echo eof; grep SigIgn /proc/$$/status; sleep 0.2`)
+		pid, err := c.I分离运行(DetachOptions{Stdout: log, Stderr: log, PidFile: pidfile, DoubleFork: doubleFork})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		out := waitFile(log, func(s string) bool { return strings.Count(s, "\n") >= 4 })
+		lines := strings.Split(out, "\n")
+		if len(lines) != 5 || lines[0] != strconv.Itoa(pid) || !strings.Contains(out, "err") || !strings.Contains(out, "eof") {
+			t.Errorf("doubleFork=%v: log = %q; want pid %d, err and eof", doubleFork, out, pid)
+		}
+		// 命令忽略的信号应与当前进程的相同，中间进程不能使它忽略SIGINT和SIGQUIT。
+		self, _ := os.ReadFile("/proc/self/status")
+		for _, line := range lines {
+			if strings.HasPrefix(line, "SigIgn:") && !strings.Contains(string(self), line+"\n") {
+				t.Errorf("doubleFork=%v: detached command has %q", doubleFork, line)
+			}
+		}
+		if got, _, err := I读取PidFile(pidfile); err != nil || got != pid {
+			t.Errorf("doubleFork=%v: I读取PidFile = %d, %v; want %d", doubleFork, got, err, pid)
+		}
+
+		st, err := readProcStat(pid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (st.ppid == os.Getpid()) == doubleFork {
+			t.Errorf("doubleFork=%v: ppid = %d, our pid %d", doubleFork, st.ppid, os.Getpid())
+		}
+		if pgid, _ := syscall.Getpgid(pid); pgid == syscall.Getpgrp() {
+			t.Errorf("doubleFork=%v: detached command shares our process group", doubleFork)
+		}
+		if !waitGone(pid) {
+			t.Errorf("doubleFork=%v: detached command did not exit", doubleFork)
+		}
+		if !doubleFork {
+			// 本包在后台回收了它，不会留下僵尸进程。
+			gone := waitFile("/proc/"+strconv.Itoa(pid)+"/stat", func(s string) bool { return s == "" })
+			if gone != "" {
+				t.Errorf("detached child %d was not reaped", pid)
+			}
+		}
+	}
+
+	c := I设置命令("true")
+	c.Cmd父类.Stdout = new(strings.Builder)
+	if _, err := c.I分离运行(DetachOptions{}); err == nil {
+		t.Errorf("I分离运行 with non-file Stdout succeeded")
+	}
+
+	c = I设置命令("true")
+	c.I设置超时(time.Second)
+	if _, err := c.I分离运行(DetachOptions{DoubleFork: true}); err == nil {
+		t.Errorf("I分离运行 with timeout and DoubleFork succeeded")
+	}
+}
@@ -0,0 +1,67 @@
+package cmd类
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandArgs(t *testing.T) {
+	env := []string{"OUT_DIR=/tmp/out", "EMPTY=", "NAME=x"}
+	tests := []struct {
+		arg    string
+		want   string
+		strict bool
+		err    string
+	}{
+		{arg: "--out=${OUT_DIR}/x", want: "--out=/tmp/out/x"},
+		{arg: "$OUT_DIR/$NAME.txt", want: "/tmp/out/x.txt"},
+		{arg: "cost: $$5 $1 $", want: "cost: $5 $1 $"},
+		{arg: "${MISSING:-def}", want: "def"},
+		{arg: "${EMPTY:-def}", want: "def"},
+		{arg: "${EMPTY-def}", want: ""},
+		{arg: "${MISSING:-$NAME/${OUT_DIR}}", want: "x//tmp/out"},
+		{arg: "[$MISSING]", want: "[]"},
+		{arg: "[$MISSING]", strict: true, err: "MISSING: parameter not set"},
+		{arg: "${MISSING:-ok}", strict: true, want: "ok"},
+		{arg: "${EMPTY}", strict: true, want: ""},
+		{arg: "${OUT_DIR:?need output dir}", want: "/tmp/out"},
+		{arg: "${EMPTY:?need value}", err: "EMPTY: need value"},
+		{arg: "${MISSING?}", err: "MISSING: parameter null or not set"},
+		{arg: "${OUT_DIR", err: "missing closing brace"},
+		{arg: "${1}", err: "bad substitution"},
+		{arg: "${NAME/x/y}", err: "bad substitution"},
+	}
+	for _, tt := range tests {
+		c := I设置命令("prog", tt.arg)
+		c.Cmd父类.Env = env
+		err := c.I展开环境变量(tt.strict)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expand %q (strict %v): error = %v; want %q", tt.arg, tt.strict, err, tt.err)
+			}
+			if c.Cmd父类.Args[1] != tt.arg {
+				t.Errorf("expand %q: Args changed on error to %q", tt.arg, c.Cmd父类.Args)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("expand %q (strict %v): %v", tt.arg, tt.strict, err)
+			continue
+		}
+		if got := c.Cmd父类.Args[1]; got != tt.want {
+			t.Errorf("expand %q (strict %v) = %q; want %q", tt.arg, tt.strict, got, tt.want)
+		}
+	}
+}
+
+func TestExpandArgsKeepsProgramName(t *testing.T) {
+	c := I设置命令("$PROG", "$A")
+	c.Cmd父类.Env = []string{"PROG=other", "A=1"}
+	if err := c.I展开环境变量(true); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"$PROG", "1"}; !reflect.DeepEqual(c.Cmd父类.Args, want) {
+		t.Errorf("Args = %q; want %q", c.Cmd父类.Args, want)
+	}
+}
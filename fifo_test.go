@@ -0,0 +1,81 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package cmd类
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNamedFifo(t *testing.T) {
+	var out bytes.Buffer
+	c := I设置命令("sh", "-c", `cat "$1" >"$2"`, "sh")
+	in, err := c.I命名管道_读取器(strings.NewReader("through fifo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	outPath, err := c.I命名管道_写入器(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Cmd父类.Args = append(c.Cmd父类.Args, in, outPath)
+	if err := c.I运行(); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != "through fifo" {
+		t.Errorf("output = %q; want %q", got, "through fifo")
+	}
+	if _, err := os.Stat(in); !os.IsNotExist(err) {
+		t.Errorf("named pipe %s still exists after I运行", in)
+	}
+}
+
+func TestNamedFifoCommands(t *testing.T) {
+	var out bytes.Buffer
+	sink := I设置命令("cat")
+	sink.Cmd父类.Stdout = &out
+	c := I设置命令("sh", "-c", `cat "$1" >"$2"`, "sh")
+	in, err := c.I命名管道_输入(I设置命令("echo", "hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	outPath, err := c.I命名管道_输出(sink)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Cmd父类.Args = append(c.Cmd父类.Args, in, outPath)
+	if err := c.I运行(); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != "hello\n" {
+		t.Errorf("output = %q; want %q", got, "hello\n")
+	}
+}
+
+func TestNamedFifoNeverOpened(t *testing.T) {
+	// 命令从不打开命名管道，I运行 不应阻塞。
+	c := I设置命令("true")
+	in, err := c.I命名管道_读取器(strings.NewReader("unused"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	outPath, err := c.I命名管道_写入器(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Cmd父类.Args = append(c.Cmd父类.Args, in, outPath)
+	done := make(chan error, 1)
+	go func() { done <- c.I运行() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("I运行 blocked on unopened named pipes")
+	}
+}
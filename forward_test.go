@@ -0,0 +1,65 @@
+//go:build unix
+
+package cmd类
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestForwardSignal(t *testing.T) {
+	c := I设置命令("sh", "-c", `trap 'echo got; exit 5' INT; echo ready; while :; do sleep 0.05; done`)
+	c.I设置信号转发()
+	out, err := c.I取标准管道()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.I运行_异步(); err != nil {
+		t.Fatal(err)
+	}
+	r := bufio.NewReader(out)
+	if line, _ := r.ReadString('\n'); line != "ready\n" {
+		t.Fatalf("first line = %q", line)
+	}
+	syscall.Kill(os.Getpid(), syscall.SIGINT)
+	line, _ := r.ReadString('\n')
+	err = c.I等待运行完成()
+	if strings.TrimSpace(line) != "got" {
+		t.Errorf("child output = %q; want got", line)
+	}
+	if ee, ok := err.(*exec.ExitError); !ok || ee.ExitCode() != 5 {
+		t.Errorf("I等待运行完成 = %v; want exit status 5", err)
+	}
+}
+
+func TestForwardSignalEscalates(t *testing.T) {
+	c := I设置命令("sh", "-c", `trap 'echo term' TERM; echo ready; while :; do sleep 0.05; done`)
+	c.I设置信号转发(syscall.SIGTERM)
+	out, err := c.I取标准管道()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.I运行_异步(); err != nil {
+		t.Fatal(err)
+	}
+	r := bufio.NewReader(out)
+	r.ReadString('\n')
+	// 等第一个信号转发并被子进程处理后再发送第二个，以免两个信号被合并。
+	syscall.Kill(os.Getpid(), syscall.SIGTERM)
+	if line, _ := r.ReadString('\n'); line != "term\n" {
+		t.Fatalf("child output = %q; want term", line)
+	}
+	syscall.Kill(os.Getpid(), syscall.SIGTERM)
+	err = c.I等待运行完成()
+	ee, ok := err.(*exec.ExitError)
+	if !ok || ee.Sys().(syscall.WaitStatus).Signal() != syscall.SIGKILL {
+		t.Errorf("I等待运行完成 = %v; want killed by SIGKILL", err)
+	}
+	if c.forwarder != nil {
+		t.Errorf("forwarder not stopped after I等待运行完成")
+	}
+}
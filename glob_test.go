@@ -0,0 +1,134 @@
+package cmd类
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestGlob(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b.go", "a.go", "x.txt", ".hidden.go", "sub/c.go", "sub/deep/d.go", ".git/e.go"} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	j := filepath.FromSlash
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"*.go", []string{"a.go", "b.go"}},
+		{".*.go", []string{".hidden.go"}},
+		{"**/*.go", []string{"a.go", "b.go", j("sub/c.go"), j("sub/deep/d.go")}},
+		{"sub/**/*.go", []string{j("sub/c.go"), j("sub/deep/d.go")}},
+		{"sub/**", []string{"sub", j("sub/c.go"), j("sub/deep"), j("sub/deep/d.go")}},
+		{"**", []string{"a.go", "b.go", "sub", j("sub/c.go"), j("sub/deep"), j("sub/deep/d.go"), "x.txt"}},
+		{"*/c.go", []string{j("sub/c.go")}},
+		{"x.txt", []string{"x.txt"}},
+		{"[ab].go", []string{"a.go", "b.go"}},
+		{"*.none", nil},
+	}
+	for _, tt := range tests {
+		got, err := globPattern(dir, tt.pattern)
+		if err != nil {
+			t.Errorf("globPattern(%q): %v", tt.pattern, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("globPattern(%q) = %q; want %q", tt.pattern, got, tt.want)
+		}
+	}
+	if _, err := globPattern(dir, "[a.go"); err == nil {
+		t.Errorf("globPattern([a.go) succeeded; want error")
+	}
+
+	abs, err := globPattern("", filepath.Join(dir, "sub", "*.go"))
+	if err != nil || len(abs) != 1 || abs[0] != filepath.Join(dir, "sub", "c.go") {
+		t.Errorf("absolute pattern = %q, %v", abs, err)
+	}
+}
+
+func TestExpandGlobArgs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b.txt", "a.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := I设置命令("prog", "-v", I通配("*.txt"), "*.txt", I通配("*.none"))
+	c.Cmd父类.Dir = dir
+	if err := c.I展开通配符(); err == nil || !strings.Contains(err.Error(), "no matches") {
+		t.Fatalf("I展开通配符 with no match = %v; want error", err)
+	}
+
+	c.I设置通配无匹配策略(Glob无匹配_原样)
+	if err := c.I展开通配符(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"prog", "-v", "a.txt", "b.txt", "*.txt", "*.none"}
+	if !reflect.DeepEqual(c.Cmd父类.Args, want) {
+		t.Errorf("Args = %q; want %q", c.Cmd父类.Args, want)
+	}
+
+	c = I设置命令("prog", I通配("*.none"))
+	c.Cmd父类.Dir = dir
+	c.I设置通配无匹配策略(Glob无匹配_忽略)
+	if err := c.I展开通配符(); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Cmd父类.Args) != 1 {
+		t.Errorf("Args = %q; want only program name", c.Cmd父类.Args)
+	}
+}
+
+func TestGlobArgMax(t *testing.T) {
+	dir := t.TempDir()
+	long := strings.Repeat("x", 200)
+	for i := 0; i < 26; i++ {
+		if err := os.WriteFile(filepath.Join(dir, string(rune('a'+i))+long), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	args := []string{"prog"}
+	for i := 0; i < argMax()/(26*200)+1; i++ {
+		args = append(args, I通配("*"))
+	}
+	c := I设置命令("prog")
+	c.Cmd父类.Args = args
+	c.Cmd父类.Dir = dir
+	if err := c.I展开通配符(); err == nil || !strings.Contains(err.Error(), "too long") {
+		t.Errorf("I展开通配符 = %v; want argument list too long", err)
+	}
+	if c.Cmd父类.Args[1] != I通配("*") {
+		t.Errorf("Args changed after failed expansion")
+	}
+}
+
+func TestGlobRun(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" || runtime.GOOS == "js" {
+		t.Skip("需要Unix工具")
+	}
+	dir := t.TempDir()
+	for _, name := range []string{"2.log", "1.log"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := I设置命令("echo", I通配("*.log"))
+	c.Cmd父类.Dir = dir
+	out, err := c.I运行_带返回值()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "1.log 2.log" {
+		t.Errorf("output = %q; want %q", got, "1.log 2.log")
+	}
+}
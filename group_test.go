@@ -0,0 +1,107 @@
+//go:build unix
+
+package cmd类
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGroupSuccess(t *testing.T) {
+	g, _ := I设置命令组(context.Background())
+	for i := 0; i < 3; i++ {
+		g.I添加(I设置命令("true"))
+	}
+	if err := g.I等待(); err != nil {
+		t.Fatalf("I等待: %v", err)
+	}
+}
+
+func TestGroupFailFast(t *testing.T) {
+	g, ctx := I设置命令组(context.Background())
+	start := time.Now()
+	g.I添加(I设置命令("sleep", "100"))
+	g.I添加(I设置命令("sh", "-c", "sleep 0.1; exit 2"))
+	g.I添加(I设置命令("sleep", "100"))
+	err := g.I等待()
+	if d := time.Since(start); d > 10*time.Second {
+		t.Errorf("I等待 took %v, want fail-fast", d)
+	}
+	var ge *GroupError
+	if !errors.As(err, &ge) {
+		t.Fatalf("I等待 = %v, want *GroupError", err)
+	}
+	var ee *exec.ExitError
+	if !errors.As(ge.Errs[1], &ee) || ee.ExitCode() != 2 {
+		t.Errorf("Errs[1] = %v, want exit status 2", ge.Errs[1])
+	}
+	for _, i := range []int{0, 2} {
+		if !errors.Is(ge.Errs[i], context.Canceled) {
+			t.Errorf("Errs[%d] = %v, want context.Canceled", i, ge.Errs[i])
+		}
+	}
+	if ctx.Err() == nil {
+		t.Errorf("group context not canceled")
+	}
+	if !strings.Contains(err.Error(), "3 of 3 commands failed") {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
+
+func TestGroupCollectAll(t *testing.T) {
+	g, _ := I设置命令组(context.Background())
+	g.I设置失败模式(Group_收集全部)
+	g.I添加(I设置命令("false"))
+	g.I添加(I设置命令("sh", "-c", "sleep 0.2"))
+	g.I添加(I设置命令("sh", "-c", "exit 3"))
+	err := g.I等待()
+	var ge *GroupError
+	if !errors.As(err, &ge) {
+		t.Fatalf("I等待 = %v, want *GroupError", err)
+	}
+	if ge.Errs[0] == nil || ge.Errs[1] != nil || ge.Errs[2] == nil {
+		t.Errorf("Errs = %v, want [error nil error]", ge.Errs)
+	}
+	if got := len(ge.Unwrap()); got != 2 {
+		t.Errorf("len(Unwrap()) = %d, want 2", got)
+	}
+}
+
+func TestGroupLimit(t *testing.T) {
+	g, _ := I设置命令组(context.Background())
+	g.I设置并发数(2)
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		g.I添加(I设置命令("sleep", "0.2"))
+	}
+	if err := g.I等待(); err != nil {
+		t.Fatalf("I等待: %v", err)
+	}
+	if d := time.Since(start); d < 400*time.Millisecond {
+		t.Errorf("4 commands with limit 2 took %v, want >= 400ms", d)
+	}
+}
+
+func TestGroupParentCancel(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	g, _ := I设置命令组(parent)
+	g.I设置失败模式(Group_收集全部)
+	g.I设置并发数(1)
+	g.I添加(I设置命令("sleep", "100"))
+	time.AfterFunc(100*time.Millisecond, cancel)
+	g.I添加(I设置命令("true"))
+	err := g.I等待()
+	var ge *GroupError
+	if !errors.As(err, &ge) {
+		t.Fatalf("I等待 = %v, want *GroupError", err)
+	}
+	for i, err := range ge.Errs {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Errs[%d] = %v, want context.Canceled", i, err)
+		}
+	}
+}
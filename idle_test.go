@@ -0,0 +1,176 @@
+//go:build unix
+
+package cmd类
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestIdleTimeout(t *testing.T) {
+	c := I设置命令("sleep", "100")
+	c.I设置无输出超时(200 * time.Millisecond)
+	start := time.Now()
+	err := c.I运行()
+	if d := time.Since(start); d > 10*time.Second {
+		t.Errorf("I运行 took %v", d)
+	}
+	var te *TimeoutError
+	if !errors.As(err, &te) || !te.Idle || !errors.Is(err, ErrTimeout) {
+		t.Fatalf("I运行 = %v, want idle *TimeoutError", err)
+	}
+	if te.Err == nil {
+		t.Errorf("TimeoutError.Err is nil")
+	}
+}
+
+func TestIdleTimeoutLingeringGrandchild(t *testing.T) {
+	// 终止sh后，sleep仍持有输出管道。
+	for _, stdout := range []io.Writer{nil, new(bytes.Buffer), os.Stdout} {
+		c := I设置命令("sh", "-c", "sleep 5; true")
+		c.I设置无输出超时(200 * time.Millisecond)
+		c.Cmd父类.Stdout = stdout
+		start := time.Now()
+		err := c.I运行()
+		if d := time.Since(start); d > 3*time.Second {
+			t.Errorf("Stdout %T: I运行 took %v", stdout, d)
+		}
+		if !errors.Is(err, ErrTimeout) {
+			t.Errorf("Stdout %T: I运行 = %v, want idle *TimeoutError", stdout, err)
+		}
+	}
+}
+
+func TestOutputWaitDelay(t *testing.T) {
+	c := I设置命令("sh", "-c", "sleep 5 & echo hi")
+	c.I设置无输出超时(time.Minute)
+	c.Cmd父类.WaitDelay = 200 * time.Millisecond
+	var out bytes.Buffer
+	c.Cmd父类.Stdout = &out
+	start := time.Now()
+	err := c.I运行()
+	if d := time.Since(start); d > 3*time.Second {
+		t.Errorf("I运行 took %v", d)
+	}
+	if !errors.Is(err, exec.ErrWaitDelay) {
+		t.Errorf("I运行 = %v, want exec.ErrWaitDelay", err)
+	}
+	if out.String() != "hi\n" {
+		t.Errorf("output = %q", out.String())
+	}
+}
+
+func TestTimeoutOutputStderr(t *testing.T) {
+	for _, idle := range []bool{false, true} {
+		c := I设置命令("sh", "-c", "echo oops >&2; exec sleep 100")
+		if idle {
+			c.I设置无输出超时(200 * time.Millisecond)
+		} else {
+			c.I设置超时(200 * time.Millisecond)
+		}
+		_, err := c.I运行_带返回值()
+		var ee *exec.ExitError
+		if !errors.Is(err, ErrTimeout) || !errors.As(err, &ee) {
+			t.Fatalf("idle %v: I运行_带返回值 = %v, want *TimeoutError wrapping *exec.ExitError", idle, err)
+		}
+		if string(ee.Stderr) != "oops\n" {
+			t.Errorf("idle %v: ExitError.Stderr = %q, want %q", idle, ee.Stderr, "oops\n")
+		}
+	}
+}
+
+func TestIdleTimeoutOutputKeepsAlive(t *testing.T) {
+	c := I设置命令("sh", "-c", `for i in 1 2 3 4 5 6 7 8; do echo $i; sleep 0.1; done`)
+	c.I设置无输出超时(time.Second)
+	var out bytes.Buffer
+	c.Cmd父类.Stdout = &out
+	if err := c.I运行(); err != nil {
+		t.Fatalf("I运行: %v", err)
+	}
+	if got := out.String(); got != "1\n2\n3\n4\n5\n6\n7\n8\n" {
+		t.Errorf("output = %q", got)
+	}
+}
+
+func TestIdleTimeoutStdoutPipe(t *testing.T) {
+	c := I设置命令("sh", "-c", `echo hi; echo err >&2; sleep 0.1; echo bye`)
+	c.I设置无输出超时(5 * time.Second)
+	r, err := c.I取标准管道()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.I运行_异步(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.I等待运行完成(); err != nil {
+		t.Fatalf("I等待运行完成: %v", err)
+	}
+	if string(b) != "hi\nbye\n" {
+		t.Errorf("output = %q, want %q", b, "hi\nbye\n")
+	}
+}
+
+func TestIdleTimeoutCombinedOutput(t *testing.T) {
+	c := I设置命令("sh", "-c", `echo a; echo b >&2; echo c`)
+	c.I设置无输出超时(5 * time.Second)
+	out, err := c.I运行_带组合返回值()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "a\nb\nc\n" {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestIdleTimeoutExcludesPause(t *testing.T) {
+	c := I设置命令("sleep", "100")
+	c.I设置无输出超时(300 * time.Millisecond)
+	if err := c.I运行_异步(); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if err := c.I暂停(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(600 * time.Millisecond)
+	if err := c.I恢复(); err != nil {
+		t.Fatal(err)
+	}
+	err := c.I等待运行完成()
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("I等待运行完成 = %v, want ErrTimeout", err)
+	}
+	if d := time.Since(start); d < 800*time.Millisecond {
+		t.Errorf("killed after %v, want pause time excluded", d)
+	}
+}
+
+func TestIdleTimeoutCPU(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("CPU progress needs /proc")
+	}
+	// 不输出但一直消耗CPU的命令。
+	c := I设置命令("sh", "-c", `end=$(($(date +%s) + 2)); while [ $(date +%s) -lt $end ]; do :; done`)
+	c.I设置无输出超时_检查CPU(500 * time.Millisecond)
+	if err := c.I运行(); err != nil {
+		t.Fatalf("I运行: %v", err)
+	}
+}
+
+func TestIdleTimeoutDetach(t *testing.T) {
+	c := I设置命令("true")
+	c.I设置无输出超时(time.Second)
+	if _, err := c.I分离运行(DetachOptions{}); err == nil {
+		t.Fatal("I分离运行 with idle timeout succeeded")
+	}
+}
@@ -0,0 +1,89 @@
+//go:build unix
+
+package cmd类
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestPauseResume(t *testing.T) {
+	c := I设置命令("sleep", "100")
+	if err := c.I暂停(); err == nil {
+		t.Errorf("I暂停 before start succeeded")
+	}
+	if err := c.I运行_异步(); err != nil {
+		t.Fatal(err)
+	}
+	if s := c.I取运行状态(); s != Run_运行中 {
+		t.Errorf("state = %d; want Run_运行中", s)
+	}
+	if err := c.I恢复(); err == nil {
+		t.Errorf("I恢复 of running command succeeded")
+	}
+	if err := c.I暂停(); err != nil {
+		t.Fatal(err)
+	}
+	if s := c.I取运行状态(); s != Run_已暂停 {
+		t.Errorf("state = %d; want Run_已暂停", s)
+	}
+	d := c.I取运行时长()
+	time.Sleep(100 * time.Millisecond)
+	if got := c.I取运行时长(); got != d {
+		t.Errorf("run time advanced while paused: %v -> %v", d, got)
+	}
+	if err := c.I恢复(); err != nil {
+		t.Fatal(err)
+	}
+	c.I终止()
+	c.I等待运行完成()
+	if s := c.I取运行状态(); s != Run_已退出 {
+		t.Errorf("state = %d; want Run_已退出", s)
+	}
+	if got := c.I取运行时长(); got >= 100*time.Millisecond {
+		t.Errorf("run time %v includes paused time", got)
+	}
+}
+
+func TestTimeoutExcludesPause(t *testing.T) {
+	c := I设置命令("sleep", "100")
+	c.I设置超时(300 * time.Millisecond)
+	start := time.Now()
+	if err := c.I运行_异步(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if err := c.I暂停(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(400 * time.Millisecond)
+	if s := c.I取运行状态(); s != Run_已暂停 {
+		t.Fatalf("timeout fired while paused, state = %d", s)
+	}
+	if err := c.I恢复(); err != nil {
+		t.Fatal(err)
+	}
+	err := c.I等待运行完成()
+	if elapsed := time.Since(start); elapsed < 700*time.Millisecond {
+		t.Errorf("command killed after %v; want paused time excluded", elapsed)
+	}
+	var te *TimeoutError
+	if !errors.Is(err, ErrTimeout) || !errors.As(err, &te) || te.Timeout != 300*time.Millisecond {
+		t.Errorf("I等待运行完成 = %v; want *TimeoutError", err)
+	}
+	var ee *exec.ExitError
+	if !errors.As(err, &ee) {
+		t.Errorf("timeout error does not wrap *exec.ExitError: %v", err)
+	}
+
+	if err := I设置命令("true").I运行(); err != nil {
+		t.Errorf("I运行 without timeout = %v", err)
+	}
+	c = I设置命令("true")
+	c.I设置超时(time.Minute)
+	if err := c.I运行(); err != nil {
+		t.Errorf("I运行 within timeout = %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+//go:build unix && !aix && !illumos && !solaris
+
+package cmd类
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// waitGone 等待进程pid退出，超时返回false。
+// 收养它的进程未必及时回收，因此在有/proc的系统上僵尸进程也视为已退出。
+func waitGone(pid int) bool {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if syscall.Kill(pid, 0) == syscall.ESRCH {
+			return true
+		}
+		if b, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat"); err == nil && strings.Contains(string(b), ") Z ") {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestProcessGroupCancel(t *testing.T) {
+	for _, mode := range []ProcessGroup模式{ProcessGroup_新进程组, ProcessGroup_新会话} {
+		ctx, cancel := context.WithCancel(context.Background())
+		c := I设置命令_上下文(ctx, "sh", "-c", "sleep 100 & echo $!; wait")
+		c.I设置进程组(mode)
+		out, err := c.I取标准管道()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.I运行_异步(); err != nil {
+			t.Fatal(err)
+		}
+		line, err := bufio.NewReader(out).ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		pid, err := strconv.Atoi(strings.TrimSpace(line))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cancel()
+		if err := c.I等待运行完成(); err == nil {
+			t.Errorf("mode %d: I等待运行完成 succeeded after cancel", mode)
+		}
+		// 组中的进程可能还是未被回收的僵尸进程，此时进程组仍然存在。
+		err = c.I终止()
+		for deadline := time.Now().Add(5 * time.Second); err == nil && time.Now().Before(deadline); err = c.I终止() {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if !errors.Is(err, os.ErrProcessDone) {
+			t.Errorf("mode %d: I终止 after exit = %v; want os.ErrProcessDone", mode, err)
+		}
+		if !waitGone(pid) {
+			syscall.Kill(pid, syscall.SIGKILL)
+			t.Errorf("mode %d: grandchild %d survived cancellation", mode, pid)
+		}
+	}
+}
+
+func TestProcessGroupCancelAfterExit(t *testing.T) {
+	// 子进程立即退出，后台的孙进程仍持有标准输出管道。
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	c := I设置命令_上下文(ctx, "sh", "-c", "sleep 4 & echo $!")
+	c.I设置进程组(ProcessGroup_新进程组)
+	var out strings.Builder
+	c.Cmd父类.Stdout = &out
+	start := time.Now()
+	c.I运行()
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("I运行 took %v after the context deadline", d)
+	}
+	if pid, err := strconv.Atoi(strings.TrimSpace(out.String())); err == nil && !waitGone(pid) {
+		syscall.Kill(pid, syscall.SIGKILL)
+		t.Errorf("grandchild %d survived cancellation", pid)
+	}
+
+	// 进程组中的其他进程还在时，命令退出后仍能向它们发送信号。
+	c = I设置命令("sh", "-c", "sleep 100 & echo $!")
+	c.I设置进程组(ProcessGroup_新进程组)
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	c.Cmd父类.Stdout = f
+	if err := c.I运行(); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := os.ReadFile(f.Name())
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err := c.I终止(); err != nil {
+		t.Errorf("I终止 with a live group member = %v", err)
+	}
+	if !waitGone(pid) {
+		syscall.Kill(pid, syscall.SIGKILL)
+		t.Errorf("grandchild %d survived I终止", pid)
+	}
+}
+
+func TestProcessGroupSignal(t *testing.T) {
+	c := I设置命令("sleep", "100")
+	if err := c.I发送信号(syscall.SIGTERM); err == nil {
+		t.Errorf("I发送信号 before start succeeded")
+	}
+	c.I设置进程组(ProcessGroup_新进程组)
+	if err := c.I运行_异步(); err != nil {
+		t.Fatal(err)
+	}
+	pgid, err := syscall.Getpgid(c.Cmd父类.Process.Pid)
+	if err != nil || pgid != c.Cmd父类.Process.Pid {
+		t.Errorf("Getpgid = %d, %v; want %d", pgid, err, c.Cmd父类.Process.Pid)
+	}
+	if err := c.I发送信号(syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+	err = c.I等待运行完成()
+	ee, ok := err.(*exec.ExitError)
+	if !ok || ee.Sys().(syscall.WaitStatus).Signal() != syscall.SIGTERM {
+		t.Errorf("I等待运行完成 = %v; want killed by SIGTERM", err)
+	}
+}
+
+func TestProcessGroupSharedSysProcAttr(t *testing.T) {
+	attr := &syscall.SysProcAttr{}
+	c := I设置命令("true")
+	c.Cmd父类.SysProcAttr = attr
+	c.I设置进程组(ProcessGroup_新会话)
+	if err := c.I运行(); err != nil {
+		t.Fatal(err)
+	}
+	if attr.Setsid || attr.Setpgid {
+		t.Errorf("caller's SysProcAttr modified: %+v", attr)
+	}
+}
@@ -0,0 +1,133 @@
+//go:build unix
+
+package cmd类
+
+import (
+	"os/exec"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestExitNotice(t *testing.T) {
+	c := I设置命令("sleep", "100")
+	if c.I退出通知() != nil {
+		t.Errorf("I退出通知 before start is not nil")
+	}
+	if err := c.I运行_异步(); err != nil {
+		t.Fatal(err)
+	}
+	fd, ok := c.I取PidFD()
+	if runtime.GOOS != "linux" && ok {
+		t.Errorf("I取PidFD = %d, true on %s", fd, runtime.GOOS)
+	}
+	t.Logf("pidfd = %d, %v", fd, ok)
+
+	done := c.I退出通知()
+	if c.I退出通知() != done {
+		t.Errorf("I退出通知 returned a different channel")
+	}
+	select {
+	case <-done:
+		t.Fatal("exit notice closed while process is running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := c.I发送信号(syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS == "linux" {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("exit notice not closed after process exit")
+		}
+		if c.Cmd父类.ProcessState != nil {
+			t.Errorf("process reaped before I等待运行完成")
+		}
+	}
+	err := c.I等待运行完成()
+	if ee, ok := err.(*exec.ExitError); !ok || ee.Sys().(syscall.WaitStatus).Signal() != syscall.SIGTERM {
+		t.Errorf("I等待运行完成 = %v; want killed by SIGTERM", err)
+	}
+	<-done
+	if _, ok := c.I取PidFD(); ok {
+		t.Errorf("pidfd still available after I等待运行完成")
+	}
+
+	c = I设置命令("true")
+	if err := c.I运行(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-c.I退出通知():
+	default:
+		t.Errorf("I退出通知 after I等待运行完成 is not closed")
+	}
+}
+
+// 等待退出通知的同时在进程退出前调用 I等待运行完成，回归检查通知不会使等待失败。
+func TestExitNoticeThenWait(t *testing.T) {
+	c := I设置命令("sleep", "0.2")
+	if err := c.I运行_异步(); err != nil {
+		t.Fatal(err)
+	}
+	done := c.I退出通知()
+	if err := c.I等待运行完成(); err != nil {
+		t.Fatalf("I等待运行完成: %v", err)
+	}
+	<-done
+}
+
+// TestExitNoticeImmediateExit 检查立即退出的进程也能收到退出通知：
+// 开始等待pidfd时进程可能正在退出，通知可能来得比退出状态早。
+func TestExitNoticeImmediateExit(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		c := I设置命令("true")
+		if err := c.I运行_异步(); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case <-c.I退出通知():
+		case <-time.After(5 * time.Second):
+			t.Fatalf("run %d: exit notice not closed after exit", i)
+		}
+		c.I等待运行完成()
+	}
+}
+
+func TestExitNoticeWithoutPidfd(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("只有Linux使用waitid")
+	}
+	c := I设置命令("sleep", "100")
+	if err := c.I运行_异步(); err != nil {
+		t.Fatal(err)
+	}
+	ch := make(chan struct{})
+	watchExit(c.Cmd父类.Process.Pid, -1, ch)
+	c.Cmd父类.Process.Kill()
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatal("waitid fallback did not report exit")
+	}
+	if c.Cmd父类.ProcessState != nil {
+		t.Errorf("process reaped by waitid fallback")
+	}
+	c.I等待运行完成()
+}
+
+func TestWaitNotStarted(t *testing.T) {
+	if err := I设置命令("true").I等待运行完成(); err == nil {
+		t.Error("I等待运行完成 before start succeeded")
+	}
+	c := I设置命令("/nonexistent/program")
+	if err := c.I运行_异步(); err == nil {
+		t.Fatal("I运行_异步 of missing program succeeded")
+	}
+	if err := c.I等待运行完成(); err == nil {
+		t.Error("I等待运行完成 after failed start succeeded")
+	}
+}
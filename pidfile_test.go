@@ -0,0 +1,123 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package cmd类
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"syscall"
+	"testing"
+)
+
+func TestPidFileCmd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "x.pid")
+	c := I设置命令("sleep", "100")
+	c.I设置PidFile(path)
+	if err := c.I运行_异步(); err != nil {
+		t.Fatal(err)
+	}
+	pid, alive, err := I读取PidFile(path)
+	if err != nil || pid != c.Cmd父类.Process.Pid || !alive {
+		t.Errorf("I读取PidFile = %d, %v, %v; want %d, true", pid, alive, err, c.Cmd父类.Process.Pid)
+	}
+
+	c2 := I设置命令("true")
+	c2.I设置PidFile(path)
+	err = c2.I运行()
+	var pe *PidFileError
+	if !errors.As(err, &pe) || pe.Pid != c.Cmd父类.Process.Pid {
+		t.Errorf("second instance I运行 = %v; want *PidFileError with pid %d", err, c.Cmd父类.Process.Pid)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("failed second instance removed the pidfile: %v", err)
+	}
+
+	c.I终止()
+	c.I等待运行完成()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("pidfile not removed after exit: %v", err)
+	}
+}
+
+func TestPidFileWriteError(t *testing.T) {
+	// 命名管道可以打开和加锁，但不能截断，因此写入进程号失败。
+	path := filepath.Join(t.TempDir(), "x.pid")
+	if err := syscall.Mkfifo(path, 0644); err != nil {
+		t.Skip(err)
+	}
+	c := I设置命令("sleep", "100")
+	c.I设置PidFile(path)
+	if err := c.I运行_异步(); err == nil {
+		c.I终止()
+		c.I等待运行完成()
+		t.Fatal("I运行_异步 succeeded with an unwritable pidfile")
+	}
+	if c.Cmd父类.Process != nil && c.Cmd父类.ProcessState == nil {
+		t.Errorf("child %d left running after writing the pidfile failed", c.Cmd父类.Process.Pid)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("pidfile not removed after failed start: %v", err)
+	}
+}
+
+func TestPidFileStale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "x.pid")
+	c := I设置命令("true")
+	if err := c.I运行(); err != nil {
+		t.Fatal(err)
+	}
+	dead := c.Cmd父类.Process.Pid
+	if err := os.WriteFile(path, []byte(strconv.Itoa(dead)+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, alive, _ := I读取PidFile(path); alive {
+		t.Errorf("I读取PidFile reports dead process %d as running", dead)
+	}
+	pf, err := I打开PidFile(path)
+	if err != nil {
+		t.Fatalf("I打开PidFile on stale file: %v", err)
+	}
+	if err := pf.I写入(os.Getpid()); err != nil {
+		t.Fatal(err)
+	}
+	if pid, alive, _ := I读取PidFile(path); pid != os.Getpid() || !alive {
+		t.Errorf("I读取PidFile = %d, %v; want %d, true", pid, alive, os.Getpid())
+	}
+	if _, err := I打开PidFile(path); err == nil {
+		t.Errorf("I打开PidFile succeeded while locked")
+	}
+	pf.I关闭()
+
+	if runtime.GOOS == "linux" {
+		// 进程号相同但启动时间不同：进程号已被重用。
+		os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+" 1\n"), 0644)
+		if _, alive, _ := I读取PidFile(path); alive {
+			t.Errorf("I读取PidFile ignores start time")
+		}
+	}
+}
+
+func TestPidFileDetach(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "svc.pid")
+	c := I设置命令("sleep", "0.3")
+	pid, err := c.I分离运行(DetachOptions{PidFile: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 锁由命令继承，本进程释放描述符后仍然有效。
+	var pe *PidFileError
+	if _, err := I打开PidFile(path); !errors.As(err, &pe) || pe.Pid != pid {
+		t.Errorf("I打开PidFile while detached command runs = %v; want *PidFileError with pid %d", err, pid)
+	}
+	if !waitGone(pid) {
+		t.Fatal("detached command did not exit")
+	}
+	pf, err := I打开PidFile(path)
+	if err != nil {
+		t.Fatalf("I打开PidFile after detached command exited: %v", err)
+	}
+	pf.I关闭()
+}
@@ -0,0 +1,106 @@
+//go:build unix
+
+package cmd类
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestPipeline(t *testing.T) {
+	var out bytes.Buffer
+	first := I设置命令("cat")
+	first.I设置Stdin_文本("b\na\nc\n")
+	last := I设置命令("sort")
+	last.Cmd父类.Stdout = &out
+	p := I设置管道(first, I设置命令("cat"), last)
+	if err := p.I运行(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out.String(), "a\nb\nc\n"; got != want {
+		t.Errorf("output = %q; want %q", got, want)
+	}
+	for i, st := range p.I取各段状态() {
+		if st == nil || !st.Success() {
+			t.Errorf("stage %d state = %v; want success", i, st)
+		}
+	}
+}
+
+func TestPipelinePipefail(t *testing.T) {
+	tests := []struct {
+		mode    Pipefail模式
+		wantErr bool
+	}{
+		{Pipefail_末段, false},
+		{Pipefail_首个失败, true},
+		{Pipefail_全部, true},
+	}
+	for _, tt := range tests {
+		p := I设置管道(I设置命令("false"), I设置命令("true"))
+		p.I设置失败模式(tt.mode)
+		err := p.I运行()
+		if tt.wantErr != (err != nil) {
+			t.Errorf("mode %d: I运行 error = %v; want error %v", tt.mode, err, tt.wantErr)
+		}
+		var ee *exec.ExitError
+		if err != nil && !errors.As(err, &ee) {
+			t.Errorf("mode %d: I运行 error = %v; want *exec.ExitError", tt.mode, err)
+		}
+		if errs := p.I取各段错误(); len(errs) != 2 || errs[0] == nil || errs[1] != nil {
+			t.Errorf("mode %d: I取各段错误 = %v; want [error <nil>]", tt.mode, errs)
+		}
+	}
+}
+
+func TestPipelineContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	p := I设置管道_上下文(ctx, I设置命令("sleep", "10"), I设置命令("sleep", "10"))
+	start := time.Now()
+	if err := p.I运行(); err != context.DeadlineExceeded {
+		t.Errorf("I运行 error = %v; want %v", err, context.DeadlineExceeded)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("pipeline took %v after context deadline", d)
+	}
+}
+
+func TestPipelineStdoutAlreadySet(t *testing.T) {
+	first := I设置命令("true")
+	first.Cmd父类.Stdout = new(bytes.Buffer)
+	if err := I设置管道(first, I设置命令("true")).I运行(); err == nil {
+		t.Error("I运行 succeeded with Stdout set on a middle stage")
+	}
+}
+
+func TestPipelineConnectFailureResetsStages(t *testing.T) {
+	first, middle, last := I设置命令("true"), I设置命令("true"), I设置命令("true")
+	last.Cmd父类.Stdin = new(bytes.Buffer)
+	if err := I设置管道(first, middle, last).I运行(); err == nil {
+		t.Fatal("I运行 succeeded with Stdin set on the last stage")
+	}
+	if first.Cmd父类.Stdout != nil || middle.Cmd父类.Stdin != nil {
+		t.Errorf("stages still connected after failure: Stdout = %v, Stdin = %v", first.Cmd父类.Stdout, middle.Cmd父类.Stdin)
+	}
+}
+
+func TestPipelineConcurrentWait(t *testing.T) {
+	p := I设置管道_上下文(context.Background(), I设置命令("true"), I设置命令("true"))
+	if err := p.I运行_异步(); err != nil {
+		t.Fatal(err)
+	}
+	errc := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() { errc <- p.I等待运行完成() }()
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-errc; err != nil {
+			t.Errorf("I等待运行完成 = %v; want nil", err)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+//go:build unix
+
+package cmd类
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestProcSubstInput(t *testing.T) {
+	c := I设置命令("cat")
+	a := c.I进程替换_输入(I设置命令("echo", "from command"))
+	b := c.I进程替换_输入读取器(strings.NewReader("from reader\n"))
+	c.Cmd父类.Args = append(c.Cmd父类.Args, a, b)
+	out, err := c.I运行_带返回值()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out), "from command\nfrom reader\n"; got != want {
+		t.Errorf("output = %q; want %q", got, want)
+	}
+}
+
+func TestProcSubstOutput(t *testing.T) {
+	var viaCmd, viaWriter bytes.Buffer
+	sub := I设置命令("cat")
+	sub.Cmd父类.Stdout = &viaCmd
+	c := I设置命令("sh", "-c", `echo one >"$1"; echo two >"$2"`, "sh")
+	c.Cmd父类.Args = append(c.Cmd父类.Args, c.I进程替换_输出(sub), c.I进程替换_输出写入器(&viaWriter))
+	if err := c.I运行(); err != nil {
+		t.Fatal(err)
+	}
+	if got := viaCmd.String(); got != "one\n" {
+		t.Errorf("command substitution got %q; want %q", got, "one\n")
+	}
+	if got := viaWriter.String(); got != "two\n" {
+		t.Errorf("writer substitution got %q; want %q", got, "two\n")
+	}
+}
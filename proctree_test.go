@@ -0,0 +1,65 @@
+//go:build linux
+
+package cmd类
+
+import (
+	"bufio"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestKillTree(t *testing.T) {
+	if _, err := exec.LookPath("setsid"); err != nil {
+		t.Skip("需要setsid")
+	}
+	// 一个后代调用setsid离开进程组，另一个忽略SIGTERM。
+	c := I设置命令("sh", "-c", `setsid sleep 100 & echo $!; sh -c 'trap "" TERM; sleep 100 & echo $!; wait' & echo $!; wait`)
+	out, err := c.I取标准管道()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.I运行_异步(); err != nil {
+		t.Fatal(err)
+	}
+	r := bufio.NewReader(out)
+	var want []int
+	for i := 0; i < 3; i++ {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		pid, _ := strconv.Atoi(strings.TrimSpace(line))
+		want = append(want, pid)
+	}
+
+	start := time.Now()
+	pids, err := c.I终止进程树(KillStep{syscall.SIGTERM, 200 * time.Millisecond}, KillStep{syscall.SIGKILL, time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pids[0] != c.Cmd父类.Process.Pid {
+		t.Errorf("pids[0] = %d; want %d", pids[0], c.Cmd父类.Process.Pid)
+	}
+	for _, pid := range want {
+		if !slices.Contains(pids, pid) {
+			t.Errorf("pid %d not in signaled set %v", pid, pids)
+		}
+		if !waitGone(pid) {
+			syscall.Kill(pid, syscall.SIGKILL)
+			t.Errorf("descendant %d survived", pid)
+		}
+	}
+	if time.Since(start) < 200*time.Millisecond {
+		t.Errorf("SIGKILL step ran before SIGTERM grace period")
+	}
+	c.I等待运行完成()
+
+	if _, err := c.I终止进程树(); err == nil {
+		t.Errorf("I终止进程树 after Wait succeeded")
+	}
+}
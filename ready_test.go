@@ -0,0 +1,166 @@
+//go:build unix
+
+package cmd类
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stopCmd 终止并回收测试启动的命令。
+func stopCmd(c *Cmd) {
+	c.I终止()
+	c.I等待运行完成()
+}
+
+func TestReadyLine(t *testing.T) {
+	c := I设置命令("sh", "-c", `echo starting; sleep 0.2; printf 'listening on 1234\r\n' >&2; exec sleep 100`)
+	c.I设置就绪条件(I就绪_输出行(regexp.MustCompile(`^listening on \d+$`)))
+	var out bytes.Buffer
+	c.Cmd父类.Stdout = &out
+	if err := c.I运行_异步(); err != nil {
+		t.Fatal(err)
+	}
+	defer stopCmd(c)
+	if err := c.I等待就绪(10 * time.Second); err != nil {
+		t.Fatalf("I等待就绪: %v", err)
+	}
+	if c.I取运行状态() != Run_运行中 {
+		t.Errorf("command not running after ready")
+	}
+}
+
+func TestReadyLineStdoutPipe(t *testing.T) {
+	c := I设置命令("sh", "-c", `echo a; echo ready; exec sleep 100`)
+	c.I设置就绪条件(I就绪_输出行(regexp.MustCompile(`ready`)))
+	r, err := c.I取标准管道()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.I运行_异步(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.I等待就绪(10 * time.Second); err != nil {
+		t.Fatalf("I等待就绪: %v", err)
+	}
+	buf := make([]byte, 64)
+	n, _ := r.Read(buf)
+	if !strings.HasPrefix("a\nready\n", string(buf[:n])) || n == 0 {
+		t.Errorf("read %q from stdout pipe", buf[:n])
+	}
+	stopCmd(c)
+}
+
+func TestReadyExited(t *testing.T) {
+	c := I设置命令("sh", "-c", `echo nope; exit 3`)
+	c.I设置就绪条件(I就绪_输出行(regexp.MustCompile(`ready`)))
+	if err := c.I运行_异步(); err != nil {
+		t.Fatal(err)
+	}
+	err := c.I等待就绪(10 * time.Second)
+	var re *ReadyError
+	if !errors.As(err, &re) {
+		t.Fatalf("I等待就绪 = %v, want *ReadyError", err)
+	}
+	var ee *exec.ExitError
+	if err := c.I等待运行完成(); !errors.As(err, &ee) || ee.ExitCode() != 3 {
+		t.Errorf("I等待运行完成 = %v, want exit status 3", err)
+	}
+}
+
+func TestReadyTimeout(t *testing.T) {
+	c := I设置命令("sleep", "100")
+	c.I设置就绪条件(I就绪_文件存在(filepath.Join(t.TempDir(), "never")))
+	if err := c.I运行_异步(); err != nil {
+		t.Fatal(err)
+	}
+	defer stopCmd(c)
+	if err := c.I等待就绪(200 * time.Millisecond); err != ErrReadyTimeout {
+		t.Fatalf("I等待就绪 = %v, want ErrReadyTimeout", err)
+	}
+	if c.I取运行状态() != Run_运行中 {
+		t.Errorf("command not running after readiness timeout")
+	}
+}
+
+func TestReadyFile(t *testing.T) {
+	dir := t.TempDir()
+	c := I设置命令("sh", "-c", `sleep 0.2; touch started; exec sleep 100`)
+	c.Cmd父类.Dir = dir
+	c.I设置就绪条件(I就绪_文件存在("started"))
+	if err := c.I运行_异步(); err != nil {
+		t.Fatal(err)
+	}
+	defer stopCmd(c)
+	if err := c.I等待就绪(10 * time.Second); err != nil {
+		t.Fatalf("I等待就绪: %v", err)
+	}
+}
+
+func TestReadyTCPAndUnix(t *testing.T) {
+	// 由测试本身在稍后开始监听，代替被启动的服务器。
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	dir := t.TempDir()
+
+	c := I设置命令("sleep", "100")
+	c.Cmd父类.Dir = dir
+	c.I设置就绪条件(I就绪_TCP端口(addr), I就绪_Unix套接字("s.sock"))
+	if err := c.I运行_异步(); err != nil {
+		t.Fatal(err)
+	}
+	defer stopCmd(c)
+	if err := c.I等待就绪(100 * time.Millisecond); err != ErrReadyTimeout {
+		t.Fatalf("I等待就绪 before listening = %v, want ErrReadyTimeout", err)
+	}
+	tl, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Skip(err)
+	}
+	defer tl.Close()
+	if err := c.I等待就绪(100 * time.Millisecond); err != ErrReadyTimeout {
+		t.Fatalf("I等待就绪 with only TCP = %v, want ErrReadyTimeout", err)
+	}
+	ul, err := net.Listen("unix", filepath.Join(dir, "s.sock"))
+	if err != nil {
+		t.Skip(err)
+	}
+	defer ul.Close()
+	if err := c.I等待就绪(10 * time.Second); err != nil {
+		t.Fatalf("I等待就绪: %v", err)
+	}
+}
+
+func TestReadyErrors(t *testing.T) {
+	c := I设置命令("true")
+	if err := c.I等待就绪(time.Second); err == nil {
+		t.Error("I等待就绪 without conditions succeeded")
+	}
+	c.I设置就绪条件(I就绪_文件存在("x"))
+	if err := c.I等待就绪(time.Second); err == nil {
+		t.Error("I等待就绪 before start succeeded")
+	}
+
+	c = I设置命令("echo", "ready")
+	c.I设置就绪条件(I就绪_输出行(nil))
+	if err := c.I运行_异步(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.I等待就绪(time.Second); err == nil {
+		t.Error("I等待就绪 with a nil regexp succeeded")
+	}
+	if err := c.I等待运行完成(); err != nil {
+		t.Errorf("I等待运行完成 = %v", err)
+	}
+}
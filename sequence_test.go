@@ -0,0 +1,78 @@
+//go:build unix
+
+package cmd类
+
+import (
+	"reflect"
+	"testing"
+)
+
+func states(res *SequenceResult) []Step状态 {
+	var s []Step状态
+	for _, step := range res.Steps {
+		s = append(s, step.State)
+	}
+	return s
+}
+
+func TestSequence(t *testing.T) {
+	tests := []struct {
+		name    string
+		seq     func() *Sequence
+		want    []Step状态
+		wantErr bool
+		aborted bool
+	}{
+		{
+			name: "false && x || y",
+			seq: func() *Sequence {
+				return I设置序列(I设置命令("false")).I并且(I设置命令("true")).I或者(I设置命令("true"))
+			},
+			want: []Step状态{Step_已运行, Step_已跳过, Step_已运行},
+		},
+		{
+			name: "true || x ; false",
+			seq: func() *Sequence {
+				return I设置序列(I设置命令("true")).I或者(I设置命令("true")).I然后(I设置命令("false"))
+			},
+			want:    []Step状态{Step_已运行, Step_已跳过, Step_已运行},
+			wantErr: true,
+		},
+		{
+			name: "set -e; false ; x",
+			seq: func() *Sequence {
+				return I设置序列(I设置命令("false")).I然后(I设置命令("true")).I设置出错即停(true)
+			},
+			want:    []Step状态{Step_已运行, Step_未执行},
+			wantErr: true,
+			aborted: true,
+		},
+		{
+			name: "set -e; false && x ; y",
+			seq: func() *Sequence {
+				return I设置序列(I设置命令("false")).I并且(I设置命令("true")).I然后(I设置命令("true")).I设置出错即停(true)
+			},
+			want: []Step状态{Step_已运行, Step_已跳过, Step_已运行},
+		},
+	}
+	for _, tt := range tests {
+		res, err := tt.seq().I运行()
+		if got := states(res); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: states = %v; want %v", tt.name, got, tt.want)
+		}
+		if tt.wantErr != (err != nil) {
+			t.Errorf("%s: error = %v; want error %v", tt.name, err, tt.wantErr)
+		}
+		if res.Aborted != tt.aborted {
+			t.Errorf("%s: Aborted = %v; want %v", tt.name, res.Aborted, tt.aborted)
+		}
+	}
+}
+
+func TestSequenceDefaults(t *testing.T) {
+	dir := t.TempDir()
+	pwd := I设置命令("sh", "-c", `test "$(pwd -P)" = "$(cd "$WANT" && pwd -P)"`)
+	if _, err := I设置序列(pwd).I设置默认目录(dir).I设置默认环境变量([]string{"WANT=" + dir}).I运行(); err != nil {
+		t.Errorf("default dir or env not applied: %v", err)
+	}
+}
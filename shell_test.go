@@ -0,0 +1,161 @@
+package cmd类
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParseCommandLineArgs(t *testing.T) {
+	tests := []struct {
+		line string
+		args [][]string
+	}{
+		{`echo hello`, [][]string{{"echo", "hello"}}},
+		{`echo 'a b' "c d" e\ f`, [][]string{{"echo", "a b", "c d", "e f"}}},
+		{`echo "a\"b\\c\d" ''`, [][]string{{"echo", `a"b\c\d`, ""}}},
+		{`echo a|tr a b | cat`, [][]string{{"echo", "a"}, {"tr", "a", "b"}, {"cat"}}},
+		{`echo x=1 {}`, [][]string{{"echo", "x=1", "{}"}}},
+		{`echo a#b`, [][]string{{"echo", "a#b"}}},
+		{"echo a \\\n b", [][]string{{"echo", "a", "b"}}},
+		{"echo a \\\n| cat", [][]string{{"echo", "a"}, {"cat"}}},
+	}
+	for _, tt := range tests {
+		cmds, err := parseCommandLine(tt.line)
+		if err != nil {
+			t.Errorf("parseCommandLine(%q): %v", tt.line, err)
+			continue
+		}
+		var got [][]string
+		for _, c := range cmds {
+			got = append(got, c.Cmd父类.Args)
+		}
+		if !reflect.DeepEqual(got, tt.args) {
+			t.Errorf("parseCommandLine(%q) args = %q; want %q", tt.line, got, tt.args)
+		}
+	}
+}
+
+func TestParseCommandLineRedirects(t *testing.T) {
+	cmds, err := parseCommandLine(`A=1 B='2 3' cmd <in >out 2>>log 2>&1 >|x 2>&2 1>&1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := cmds[0]
+	if !reflect.DeepEqual(c.Cmd父类.Args, []string{"cmd"}) {
+		t.Errorf("args = %q; want [cmd]", c.Cmd父类.Args)
+	}
+	if env := c.Cmd父类.Env; len(env) < 2 || env[len(env)-2] != "A=1" || env[len(env)-1] != "B=2 3" {
+		t.Errorf("env does not end with assignments: %q", env)
+	}
+	want := []redirect{
+		{fd: 0, op: "<", path: "in"},
+		{fd: 1, op: ">", path: "out"},
+		{fd: 2, op: ">>", path: "log"},
+		{fd: 2, op: ">&", dupFd: 1},
+		{fd: 1, op: ">", path: "x"},
+		{fd: 2, op: ">&", dupFd: 2},
+		{fd: 1, op: ">&", dupFd: 1},
+	}
+	if !reflect.DeepEqual(c.redirs, want) {
+		t.Errorf("redirs = %+v; want %+v", c.redirs, want)
+	}
+}
+
+func TestParseCommandLineRejects(t *testing.T) {
+	for _, line := range []string{
+		``,
+		`echo $HOME`,
+		`echo "$HOME"`,
+		"echo `date`",
+		`echo $(date)`,
+		`ls *.go`,
+		`ls file?`,
+		`ls ~`,
+		`a; b`,
+		`a && b`,
+		`a || b`,
+		`a &`,
+		`(a)`,
+		`# comment`,
+		`cat <<EOF`,
+		`echo 'unterminated`,
+		`echo "unterminated`,
+		`echo \`,
+		`a |`,
+		`| a`,
+		`a | | b`,
+		`a >`,
+		`a 3>x`,
+		`a 1<x`,
+		`a 2>&3`,
+		`a 2>&-`,
+		`A=1`,
+		"a\nb",
+	} {
+		_, err := parseCommandLine(line)
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("parseCommandLine(%q) error = %v; want *ParseError", line, err)
+		}
+	}
+}
+
+func TestParseCommandLineRun(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" || runtime.GOOS == "js" {
+		t.Skip("需要Unix工具")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "in.txt"), []byte("b\nfoo\na\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	p, err := I解析命令行(`grep -v foo in.txt | MSG='to stderr' sh -c 'echo "$MSG" >&2; sort' > out.txt 2>&1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range p.I取命令组() {
+		c.Cmd父类.Dir = dir
+	}
+	if err := p.I运行(); err != nil {
+		t.Fatal(err)
+	}
+	out, err := os.ReadFile(filepath.Join(dir, "out.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out), "to stderr\na\nb\n"; got != want {
+		t.Errorf("out.txt = %q; want %q", got, want)
+	}
+
+	p, err = I解析命令行(`echo more >> out.txt`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.I取命令组()[0].Cmd父类.Dir = dir
+	if err := p.I运行(); err != nil {
+		t.Fatal(err)
+	}
+	out, _ = os.ReadFile(filepath.Join(dir, "out.txt"))
+	if !strings.HasSuffix(string(out), "b\nmore\n") {
+		t.Errorf("out.txt after >> = %q", out)
+	}
+
+	// 把描述符复制到自身不改变它。
+	p, err = I解析命令行(`sh -c 'echo out; echo err >&2' > so.txt 2> se.txt 2>&2 1>&1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.I取命令组()[0].Cmd父类.Dir = dir
+	if err := p.I运行(); err != nil {
+		t.Fatal(err)
+	}
+	so, _ := os.ReadFile(filepath.Join(dir, "so.txt"))
+	se, _ := os.ReadFile(filepath.Join(dir, "se.txt"))
+	if string(so) != "out\n" || string(se) != "err\n" {
+		t.Errorf("so.txt = %q, se.txt = %q; want \"out\\n\", \"err\\n\"", so, se)
+	}
+}
@@ -0,0 +1,100 @@
+package cmd类
+
+import (
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", []string{}},
+		{"  a  b\tc\n", []string{"a", "b", "c"}},
+		{`'a b' "c \"d\" \$e" f\ g`, []string{"a b", `c "d" $e`, "f g"}},
+		{`a\` + "\n" + `b`, []string{"ab"}},
+		{"a \\\n b", []string{"a", "b"}},
+		{"\\\n", []string{}},
+		{"'' \\\n", []string{""}},
+		{`$HOME *.go a|b >x`, []string{"$HOME", "*.go", "a|b", ">x"}},
+		{`"" ''`, []string{"", ""}},
+		{`'it'\''s'`, []string{"it's"}},
+	}
+	for _, tt := range tests {
+		got, err := I分割参数(tt.in)
+		if err != nil {
+			t.Errorf("I分割参数(%q): %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("I分割参数(%q) = %q; want %q", tt.in, got, tt.want)
+		}
+	}
+	for _, bad := range []string{`'a`, `"a`, `a\`} {
+		if _, err := I分割参数(bad); err == nil {
+			t.Errorf("I分割参数(%q) succeeded; want error", bad)
+		}
+	}
+}
+
+func TestQuoteArgs(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", "''"},
+		{"abc", "abc"},
+		{"--out=/tmp/x,y", "--out=/tmp/x,y"},
+		{"a b", "'a b'"},
+		{"it's", `'it'\''s'`},
+		{"$HOME", "'$HOME'"},
+		{"*", "'*'"},
+		{"a\nb", "'a\nb'"},
+	}
+	for _, tt := range tests {
+		if got := I引用参数(tt.in); got != tt.want {
+			t.Errorf("I引用参数(%q) = %s; want %s", tt.in, got, tt.want)
+		}
+	}
+
+	args := []string{"A=1", "x y", "it's", "", `\`, "$(rm -rf /)", "a\tb\nc"}
+	joined := I拼接参数(args)
+	if !strings.HasPrefix(joined, "'A=1' ") {
+		t.Errorf("I拼接参数 did not quote leading assignment: %s", joined)
+	}
+	back, err := I分割参数(joined)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, args) {
+		t.Errorf("I分割参数(I拼接参数(%q)) = %q", args, back)
+	}
+}
+
+func TestShellString(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" || runtime.GOOS == "js" {
+		t.Skip("需要Unix工具")
+	}
+	c := I设置命令("sh", "-c", `echo "$GREETING" "$1"; pwd`, "sh", "it's me")
+	c.Cmd父类.Dir = t.TempDir()
+	c.Cmd父类.Env = append(os.Environ(), "GREETING=hello world")
+	s := c.ShellString()
+	if !strings.HasPrefix(s, "(cd ") || !strings.Contains(s, "GREETING='hello world' ") {
+		t.Errorf("ShellString() = %s", s)
+	}
+
+	want, err := c.I运行_带返回值()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := I设置命令("sh", "-c", s).I运行_带返回值()
+	if err != nil {
+		t.Fatalf("running %s: %v", s, err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("ShellString() output = %q; want %q", got, want)
+	}
+}
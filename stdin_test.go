@@ -0,0 +1,158 @@
+//go:build unix
+
+package cmd类
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStdinSources(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "in.txt")
+	if err := os.WriteFile(path, []byte("from file"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	lines := make(chan string, 2)
+	lines <- "a"
+	lines <- "b"
+	close(lines)
+
+	tests := []struct {
+		name string
+		set  func(c *Cmd)
+		want string
+	}{
+		{"文本", func(c *Cmd) { c.I设置Stdin_文本("hello") }, "hello"},
+		{"字节集", func(c *Cmd) { c.I设置Stdin_字节集([]byte("bytes")) }, "bytes"},
+		{"文件", func(c *Cmd) { c.I设置Stdin_文件(path) }, "from file"},
+		{"生成函数", func(c *Cmd) {
+			c.I设置Stdin_生成函数(func(w io.Writer) error {
+				_, err := io.WriteString(w, "generated")
+				return err
+			})
+		}, "generated"},
+		{"行通道", func(c *Cmd) { c.I设置Stdin_行通道(lines) }, "a\nb\n"},
+	}
+	for _, tt := range tests {
+		c := I设置命令("cat")
+		tt.set(c)
+		out, err := c.I运行_带返回值()
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if string(out) != tt.want {
+			t.Errorf("%s: output = %q; want %q", tt.name, out, tt.want)
+		}
+	}
+}
+
+func TestStdinGeneratorError(t *testing.T) {
+	want := errors.New("generator failed")
+	c := I设置命令("cat")
+	c.I设置Stdin_生成函数(func(io.Writer) error { return want })
+	if _, err := c.I运行_带返回值(); err != want {
+		t.Errorf("I运行_带返回值 error = %v; want %v", err, want)
+	}
+}
+
+func TestStdinAlreadySet(t *testing.T) {
+	c := I设置命令("cat")
+	c.Cmd父类.Stdin = os.Stdin
+	c.I设置Stdin_文本("x")
+	if err := c.I运行(); err == nil {
+		t.Error("I运行 succeeded with both Cmd父类.Stdin and I设置Stdin_文本 set")
+	}
+}
+
+func TestStdinLineChannelNotClosed(t *testing.T) {
+	// 子进程不读取标准输入就退出；未关闭的通道不应使 I运行 阻塞。
+	c := I设置命令("true")
+	c.I设置Stdin_行通道(make(chan string))
+	if err := c.I运行(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStdinBrokenPipePolicy(t *testing.T) {
+	// 子进程不读取标准输入就退出，写入足够多的数据以确保遇到断管错误。
+	data := make([]byte, 4<<20)
+	tests := []struct {
+		policy  Stdin断管策略
+		wantErr bool
+	}{
+		{Stdin断管_成功时忽略, false},
+		{Stdin断管_总是忽略, false},
+		{Stdin断管_总是报告, true},
+	}
+	for _, tt := range tests {
+		c := I设置命令("true")
+		c.I设置Stdin_字节集(data)
+		c.I设置Stdin断管策略(tt.policy)
+		err := c.I运行()
+		if tt.wantErr != (err != nil) {
+			t.Errorf("policy %d: I运行 error = %v; want error %v", tt.policy, err, tt.wantErr)
+		}
+		if err != nil && !skipStdinCopyError(err) {
+			t.Errorf("policy %d: I运行 error = %v; want broken pipe", tt.policy, err)
+		}
+	}
+}
+
+func TestStdinPipeBrokenPipePolicy(t *testing.T) {
+	for _, policy := range []Stdin断管策略{Stdin断管_总是忽略, Stdin断管_总是报告} {
+		c := I设置命令("true")
+		c.I设置Stdin断管策略(policy)
+		w, err := c.I取Stdin管道()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.I运行_异步(); err != nil {
+			t.Fatal(err)
+		}
+		_, werr := w.Write(make([]byte, 4<<20))
+		err = c.I等待运行完成()
+		switch policy {
+		case Stdin断管_总是忽略:
+			if werr != nil || err != nil {
+				t.Errorf("总是忽略: Write error = %v, I等待运行完成 error = %v; want nil, nil", werr, err)
+			}
+		case Stdin断管_总是报告:
+			if werr == nil || err == nil {
+				t.Errorf("总是报告: Write error = %v, I等待运行完成 error = %v; want errors", werr, err)
+			}
+		}
+	}
+}
+
+func TestStdinMemFile(t *testing.T) {
+	data := []byte("seekable input")
+	f, cleanup, err := newMemFile(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fi, err := f.Stat()
+	f.Close()
+	if cleanup != nil {
+		cleanup()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fi.Mode().IsRegular() || fi.Size() != int64(len(data)) {
+		t.Errorf("newMemFile: mode %v, size %d; want regular file of size %d", fi.Mode(), fi.Size(), len(data))
+	}
+
+	c := I设置命令("cat")
+	c.I设置Stdin_内存文件(data)
+	out, err := c.I运行_带返回值()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != string(data) {
+		t.Errorf("output = %q; want %q", out, data)
+	}
+}
@@ -0,0 +1,72 @@
+//go:build linux
+
+package cmd类
+
+import (
+	"bufio"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSubreaper(t *testing.T) {
+	adopted := make(chan AdoptedProcess, 10)
+	if err := I启用收养回收(func(p AdoptedProcess) { adopted <- p }); err != nil {
+		t.Skipf("I启用收养回收: %v", err)
+	}
+	defer I停用收养回收()
+
+	// sh退出后，后台的sh成为孤儿并被本进程收养。
+	c := I设置命令("sh", "-c", `sh -c 'sleep 0.2; exit 7' </dev/null >/dev/null & echo $!`)
+	out, err := c.I运行_带返回值()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(out)))
+
+	// 回收期间命令自己的退出状态不受影响。
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := I设置命令("sh", "-c", "exit 3").I运行()
+			if ee, ok := err.(*exec.ExitError); !ok || ee.ExitCode() != 3 {
+				t.Errorf("I运行 = %v; want exit status 3", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	select {
+	case p := <-adopted:
+		if p.Pid != pid || p.ExitCode != 7 || p.Name != "sh" {
+			t.Errorf("adopted = %+v; want pid %d, sh, exit code 7", p, pid)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("orphan was not reaped")
+	}
+}
+
+func TestSubreaperStartRace(t *testing.T) {
+	if err := I启用收养回收(nil); err != nil {
+		t.Skipf("I启用收养回收: %v", err)
+	}
+	defer I停用收养回收()
+	for i := 0; i < 50; i++ {
+		c := I设置命令("true")
+		out, _ := c.I取标准管道()
+		if err := c.I运行_异步(); err != nil {
+			t.Fatal(err)
+		}
+		bufio.NewReader(out).ReadString('\n')
+		time.Sleep(time.Millisecond)
+		reapAdopted()
+		if err := c.I等待运行完成(); err != nil {
+			t.Fatalf("I等待运行完成 after reaper ran = %v", err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+//go:build unix
+
+package cmd类
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestWaitTimeout(t *testing.T) {
+	c := I设置命令("sleep", "100")
+	if err := c.I等待运行完成_超时(time.Millisecond); err == nil {
+		t.Errorf("I等待运行完成_超时 before start succeeded")
+	}
+	if err := c.I运行_异步(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.I等待运行完成_超时(50 * time.Millisecond); !errors.Is(err, ErrWaitTimeout) {
+		t.Fatalf("I等待运行完成_超时 = %v; want ErrWaitTimeout", err)
+	}
+	if err := c.I发送信号(nil); err == nil {
+		t.Errorf("I发送信号(nil) succeeded")
+	}
+	if c.I取运行状态() != Run_运行中 || c.isReaped() {
+		t.Errorf("command stopped after wait timeout")
+	}
+	c.I终止()
+	if err := c.I等待运行完成(); err == nil || errors.Is(err, ErrWaitTimeout) {
+		t.Errorf("I等待运行完成 after kill = %v; want exit error", err)
+	}
+	if err := c.I等待运行完成(); err == nil {
+		t.Errorf("second I等待运行完成 succeeded")
+	}
+
+	c = I设置命令("true")
+	if err := c.I运行_异步(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.I等待运行完成_超时(5 * time.Second); err != nil {
+		t.Errorf("I等待运行完成_超时 = %v", err)
+	}
+	if err := c.I等待运行完成_超时(time.Second); err == nil || errors.Is(err, ErrWaitTimeout) {
+		t.Errorf("I等待运行完成_超时 after result was taken = %v; want Wait was already called", err)
+	}
+}
+
+func TestWaitAny(t *testing.T) {
+	cmds := []*Cmd{
+		I设置命令("sleep", "100"),
+		I设置命令("sh", "-c", "sleep 0.1; exit 4"),
+		I设置命令("sleep", "100"),
+	}
+	for _, c := range cmds {
+		if err := c.I运行_异步(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	first, err := I等待任一(cmds...)
+	if first != cmds[1] {
+		t.Errorf("I等待任一 returned %v; want %v", first, cmds[1])
+	}
+	if ee, ok := err.(*exec.ExitError); !ok || ee.ExitCode() != 4 || first.Cmd父类.ProcessState.ExitCode() != 4 {
+		t.Errorf("I等待任一 error = %v; want exit status 4", err)
+	}
+
+	cmds[2].I终止()
+	rest := []*Cmd{cmds[0], cmds[2]}
+	if c, _ := I等待任一(rest...); c != cmds[2] {
+		t.Errorf("I等待任一 returned %v; want the killed command", c)
+	}
+	cmds[0].I终止()
+	if err := cmds[0].I等待运行完成(); err == nil {
+		t.Errorf("I等待运行完成 of killed command succeeded")
+	}
+	if _, err := I等待任一(); err == nil {
+		t.Errorf("I等待任一 with no commands succeeded")
+	}
+}